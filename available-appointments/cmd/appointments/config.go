@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+// config describes the resources and appointment types of a practice.
+type config struct {
+	Resources []struct {
+		ID          string `json:"id"`
+		Role        string `json:"role"`
+		Overbooking *struct {
+			MaxConcurrent    int      `json:"max_concurrent"`
+			AppointmentTypes []string `json:"appointment_types"`
+		} `json:"overbooking"`
+	} `json:"resources"`
+	Types []struct {
+		ID                string   `json:"id"`
+		Name              string   `json:"name"`
+		Duration          string   `json:"duration"`
+		RequiredRoles     []string `json:"required_roles"`
+		EligibleResources []string `json:"eligible_resources"`
+	} `json:"types"`
+}
+
+func loadConfig(path string) ([]appointment.Resource, *appointment.Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var resources []appointment.Resource
+	for _, r := range c.Resources {
+		resource := appointment.Resource{ID: r.ID, Role: r.Role}
+		if r.Overbooking != nil {
+			resource.Overbooking = &appointment.OverbookingPolicy{
+				MaxConcurrent:    r.Overbooking.MaxConcurrent,
+				AppointmentTypes: r.Overbooking.AppointmentTypes,
+			}
+		}
+		resources = append(resources, resource)
+	}
+
+	var types []appointment.AppointmentType
+	for _, t := range c.Types {
+		duration, err := time.ParseDuration(t.Duration)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: type %q: %w", path, t.ID, err)
+		}
+
+		types = append(types, appointment.AppointmentType{
+			ID:                t.ID,
+			Name:              t.Name,
+			Duration:          duration,
+			RequiredRoles:     t.RequiredRoles,
+			EligibleResources: t.EligibleResources,
+		})
+	}
+
+	catalog, err := appointment.NewCatalog(types...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return resources, catalog, nil
+}
+
+// loadType loads the config and looks up the appointment type with the
+// given ID.
+func loadType(path, typeID string) ([]appointment.Resource, appointment.AppointmentType, error) {
+	if path == "" {
+		return nil, appointment.AppointmentType{}, fmt.Errorf("-type requires -config")
+	}
+
+	resources, catalog, err := loadConfig(path)
+	if err != nil {
+		return nil, appointment.AppointmentType{}, err
+	}
+
+	t, found := catalog.Get(typeID)
+	if !found {
+		return nil, appointment.AppointmentType{}, fmt.Errorf("unknown appointment type %q", typeID)
+	}
+
+	return resources, t, nil
+}
@@ -0,0 +1,425 @@
+// Command appointments inspects and changes an appointment calendar stored
+// in a JSON, CSV or ICS file, or in a SQL database.
+//
+// Usage:
+//
+//	appointments availability CALENDAR [-from DATE] [-format table|json] [-config FILE -type ID]
+//	appointments open CALENDAR -start TIME -end TIME [-resources ID,...]
+//	appointments book CALENDAR -start TIME (-end TIME | -config FILE -type ID) [-resources ID,...]
+//	appointments cancel CALENDAR -id ID
+//	appointments overbooked CALENDAR [-from DATE]
+//
+// where CALENDAR is either -events FILE or -driver NAME -dsn DSN. The
+// database driver has to be linked into the binary with a blank import.
+// Dates are given as 2006-01-02, times as RFC 3339.
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+	"github.com/baschtl/appointment-system/pkg/eventfile"
+	"github.com/baschtl/appointment-system/pkg/sqlstore"
+)
+
+const usage = `usage: appointments <command> [flags]
+
+commands:
+  availability  print the available slots of a week
+  open          add an opening
+  book          book an appointment
+  cancel        cancel an appointment
+  overbooked    print the overbooked slots of a week
+
+Run "appointments <command> -h" for the flags of a command.
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "appointments: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+
+	switch args[0] {
+	case "availability":
+		return runAvailability(args[1:], stdout)
+	case "open":
+		return runOpen(args[1:], stdout)
+	case "book":
+		return runBook(args[1:], stdout)
+	case "cancel":
+		return runCancel(args[1:], stdout)
+	case "overbooked":
+		return runOverbooked(args[1:], stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func runAvailability(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("availability", flag.ContinueOnError)
+	source := calendarFlags(fs)
+	from := fs.String("from", time.Now().UTC().Format(time.DateOnly), "first day of the week to show")
+	format := fs.String("format", "table", "output format: table or json")
+	configPath := fs.String("config", "", "resources and appointment types (.json)")
+	typeID := fs.String("type", "", "only show slots for this appointment type")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := source.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	startDate, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+
+	var rows []slotRow
+	if *typeID == "" {
+		slots, err := appointment.CalculateAvailableSlotsValidated(store, startDate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: ignoring invalid events: %v\n", err)
+		}
+		for _, daySlots := range slots {
+			for _, s := range daySlots {
+				rows = append(rows, slotRow{Start: s.Start, End: s.End})
+			}
+		}
+	} else {
+		resources, t, err := loadType(*configPath, *typeID)
+		if err != nil {
+			return err
+		}
+
+		for _, daySlots := range appointment.CalculateAvailableSlotsForType(store, resources, t, startDate) {
+			for _, s := range daySlots {
+				row := slotRow{Start: s.Start, End: s.End}
+				for _, r := range s.Resources {
+					row.Resources = append(row.Resources, r.ID)
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Start.Before(rows[j].Start)
+	})
+
+	if err := store.check(nil); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table":
+		return printTable(stdout, rows)
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	default:
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+}
+
+func runOpen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("open", flag.ContinueOnError)
+	source := calendarFlags(fs)
+	start := fs.String("start", "", "start of the opening")
+	end := fs.String("end", "", "end of the opening")
+	resources := fs.String("resources", "", "comma separated resource IDs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := source.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	opening, err := newEvent(store, appointment.KindOpening, *start, *end, *resources)
+	if err != nil {
+		return err
+	}
+
+	if err := store.AddEvent(opening); err != nil {
+		return store.check(err)
+	}
+
+	fmt.Fprintf(stdout, "added opening %d\n", opening.ID)
+
+	return store.save()
+}
+
+func runBook(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("book", flag.ContinueOnError)
+	source := calendarFlags(fs)
+	start := fs.String("start", "", "start of the appointment")
+	end := fs.String("end", "", "end of the appointment, derived from -type if empty")
+	resources := fs.String("resources", "", "comma separated resource IDs")
+	configPath := fs.String("config", "", "resources and appointment types (.json)")
+	typeID := fs.String("type", "", "appointment type")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := source.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var known []appointment.Resource
+	var catalog *appointment.Catalog
+	if *configPath != "" {
+		if known, catalog, err = loadConfig(*configPath); err != nil {
+			return err
+		}
+	}
+
+	if *end == "" && *typeID != "" {
+		if catalog == nil {
+			return errors.New("-type requires -config")
+		}
+
+		t, found := catalog.Get(*typeID)
+		if !found {
+			return fmt.Errorf("unknown appointment type %q", *typeID)
+		}
+
+		startsAt, err := time.Parse(time.RFC3339, *start)
+		if err != nil {
+			return fmt.Errorf("invalid -start: %w", err)
+		}
+		*end = startsAt.Add(t.Duration).Format(time.RFC3339)
+	}
+
+	booking, err := newEvent(store, appointment.KindAppointment, *start, *end, *resources)
+	if err != nil {
+		return err
+	}
+	booking.Type = *typeID
+
+	if err := appointment.Book(store, known, booking); err != nil {
+		return store.check(err)
+	}
+
+	fmt.Fprintf(stdout, "booked appointment %d\n", booking.ID)
+
+	return store.save()
+}
+
+func runCancel(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	source := calendarFlags(fs)
+	id := fs.Int("id", 0, "ID of the appointment to cancel")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := source.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := appointment.Cancel(store, *id); err != nil {
+		return store.check(err)
+	}
+
+	fmt.Fprintf(stdout, "cancelled appointment %d\n", *id)
+
+	return store.save()
+}
+
+func runOverbooked(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("overbooked", flag.ContinueOnError)
+	source := calendarFlags(fs)
+	from := fs.String("from", time.Now().UTC().Format(time.DateOnly), "first day of the week to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := source.open()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	startDate, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+
+	overbooked := appointment.CalculateOverbookedSlots(store, startDate)
+	if err := store.check(nil); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "RESOURCE\tDATE\tSTART\tEND\tAPPOINTMENTS")
+	for _, s := range overbooked {
+		ids := make([]string, 0, len(s.AppointmentIDs))
+		for _, id := range s.AppointmentIDs {
+			ids = append(ids, strconv.Itoa(id))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			s.Resource, s.Start.Format(time.DateOnly), s.Start.Format("15:04"), s.End.Format("15:04"), strings.Join(ids, ", "))
+	}
+
+	return tw.Flush()
+}
+
+// slotRow is a single line of the availability output.
+type slotRow struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Resources []string  `json:"resources,omitempty"`
+}
+
+func printTable(w io.Writer, rows []slotRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "DATE\tSTART\tEND\tRESOURCES")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			r.Start.Format(time.DateOnly), r.Start.Format("15:04"), r.End.Format("15:04"), strings.Join(r.Resources, ", "))
+	}
+
+	return tw.Flush()
+}
+
+// calendarSource holds the flags that select where the events are kept.
+type calendarSource struct {
+	eventsPath *string
+	driver     *string
+	dsn        *string
+}
+
+func calendarFlags(fs *flag.FlagSet) calendarSource {
+	return calendarSource{
+		eventsPath: fs.String("events", "", "event file (.json, .csv or .ics)"),
+		driver:     fs.String("driver", "", "database/sql driver of the event database"),
+		dsn:        fs.String("dsn", "", "data source name of the event database"),
+	}
+}
+
+// calendar is the store a command works on, together with what is needed to
+// persist it.
+type calendar struct {
+	appointment.Store
+
+	path string // event file, empty if the events are kept in db
+	db   *sql.DB
+	sql  *sqlstore.Store
+}
+
+func (s calendarSource) open() (*calendar, error) {
+	switch {
+	case *s.eventsPath != "" && *s.driver != "":
+		return nil, errors.New("-events and -driver are mutually exclusive")
+	case *s.driver != "":
+		db, err := sql.Open(*s.driver, *s.dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		store, err := sqlstore.New(db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		return &calendar{Store: store, db: db, sql: store}, nil
+	case *s.eventsPath != "":
+		events, err := eventfile.ReadFile(*s.eventsPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		return &calendar{Store: appointment.NewMemoryStore(events), path: *s.eventsPath}, nil
+	default:
+		return nil, errors.New("-events or -driver is required")
+	}
+}
+
+// check returns the error the database ran into while the command read from
+// it, since that is the cause of err if there is one, and err otherwise.
+func (c *calendar) check(err error) error {
+	if c.sql != nil {
+		if dbErr := c.sql.Err(); dbErr != nil {
+			return dbErr
+		}
+	}
+
+	return err
+}
+
+// save persists the changes of the command. A database already holds them.
+func (c *calendar) save() error {
+	if c.sql != nil {
+		return c.check(nil)
+	}
+
+	return eventfile.WriteFile(c.path, c.Events())
+}
+
+func (c *calendar) Close() error {
+	if c.db != nil {
+		return c.db.Close()
+	}
+
+	return nil
+}
+
+func newEvent(store appointment.Store, kind, start, end, resources string) (appointment.Event, error) {
+	startsAt, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return appointment.Event{}, fmt.Errorf("invalid -start: %w", err)
+	}
+
+	endsAt, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return appointment.Event{}, fmt.Errorf("invalid -end: %w", err)
+	}
+
+	e := appointment.Event{
+		ID:       appointment.NextID(store),
+		Kind:     kind,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	}
+	if resources != "" {
+		e.Resources = strings.Split(resources, ",")
+	}
+
+	return e, nil
+}
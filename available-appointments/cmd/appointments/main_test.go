@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baschtl/appointment-system/internal/memsql"
+	"github.com/baschtl/appointment-system/pkg/appointment"
+	"github.com/baschtl/appointment-system/pkg/eventfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs the command line and returns what it printed.
+func runCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	err := run(args, &stdout)
+
+	return stdout.String(), err
+}
+
+func TestRun(t *testing.T) {
+	for _, ext := range []string{"json", "csv", "ics"} {
+		t.Run("should open, book and cancel in a "+ext+" file", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "events."+ext)
+
+			out, err := runCommand(t, "open", "-events", path, "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T10:00:00Z", "-resources", "dr-smith")
+			require.NoError(t, err)
+			assert.Equal(t, "added opening 1\n", out)
+
+			out, err = runCommand(t, "book", "-events", path, "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T09:30:00Z", "-resources", "dr-smith")
+			require.NoError(t, err)
+			assert.Equal(t, "booked appointment 2\n", out)
+
+			events, err := eventfile.ReadFile(path)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			assert.Equal(t, appointment.KindOpening, events[0].Kind)
+			assert.Equal(t, appointment.KindAppointment, events[1].Kind)
+			assert.Equal(t, []string{"dr-smith"}, events[1].Resources)
+
+			out, err = runCommand(t, "cancel", "-events", path, "-id", "2")
+			require.NoError(t, err)
+			assert.Equal(t, "cancelled appointment 2\n", out)
+
+			events, err = eventfile.ReadFile(path)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, 1, events[0].ID)
+		})
+	}
+
+	t.Run("should not change the file if a booking is rejected", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.json")
+
+		_, err := runCommand(t, "open", "-events", path, "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T10:00:00Z", "-resources", "dr-smith")
+		require.NoError(t, err)
+		_, err = runCommand(t, "book", "-events", path, "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T09:30:00Z", "-resources", "dr-smith")
+		require.NoError(t, err)
+
+		_, err = runCommand(t, "book", "-events", path, "-start", "2025-04-07T09:15:00Z", "-end", "2025-04-07T09:45:00Z", "-resources", "dr-smith")
+		assert.ErrorIs(t, err, appointment.ErrSlotUnavailable)
+
+		_, err = runCommand(t, "book", "-events", path, "-start", "2025-04-07T11:00:00Z", "-end", "2025-04-07T11:30:00Z", "-resources", "dr-smith")
+		assert.ErrorIs(t, err, appointment.ErrNoOpening)
+
+		_, err = runCommand(t, "cancel", "-events", path, "-id", "1")
+		assert.ErrorIs(t, err, appointment.ErrNotAnAppointment)
+
+		events, err := eventfile.ReadFile(path)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
+
+	t.Run("should print the availability of the booked file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.json")
+
+		_, err := runCommand(t, "open", "-events", path, "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T10:00:00Z", "-resources", "dr-smith")
+		require.NoError(t, err)
+		_, err = runCommand(t, "book", "-events", path, "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T09:30:00Z", "-resources", "dr-smith")
+		require.NoError(t, err)
+
+		out, err := runCommand(t, "availability", "-events", path, "-from", "2025-04-07", "-format", "json")
+		require.NoError(t, err)
+
+		var rows []slotRow
+		require.NoError(t, json.Unmarshal([]byte(out), &rows))
+		require.Len(t, rows, 1)
+		assert.Equal(t, "2025-04-07T09:30:00Z", rows[0].Start.Format(time.RFC3339))
+		assert.Equal(t, "2025-04-07T10:00:00Z", rows[0].End.Format(time.RFC3339))
+
+		out, err = runCommand(t, "availability", "-events", path, "-from", "2025-04-07")
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(out, "\n"))
+	})
+
+	t.Run("should open, book and cancel in a database", func(t *testing.T) {
+		db := []string{"-driver", memsql.DriverName, "-dsn", t.Name()}
+
+		out, err := runCommand(t, append([]string{"open", "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T10:00:00Z", "-resources", "dr-smith"}, db...)...)
+		require.NoError(t, err)
+		assert.Equal(t, "added opening 1\n", out)
+
+		out, err = runCommand(t, append([]string{"book", "-start", "2025-04-07T09:00:00Z", "-end", "2025-04-07T09:30:00Z", "-resources", "dr-smith"}, db...)...)
+		require.NoError(t, err)
+		assert.Equal(t, "booked appointment 2\n", out)
+
+		out, err = runCommand(t, append([]string{"availability", "-from", "2025-04-07"}, db...)...)
+		require.NoError(t, err)
+		assert.Contains(t, out, "09:30  10:00")
+
+		out, err = runCommand(t, append([]string{"cancel", "-id", "2"}, db...)...)
+		require.NoError(t, err)
+		assert.Equal(t, "cancelled appointment 2\n", out)
+
+		_, err = runCommand(t, append([]string{"cancel", "-id", "2"}, db...)...)
+		assert.ErrorIs(t, err, appointment.ErrEventNotFound)
+	})
+
+	t.Run("should report a failing database", func(t *testing.T) {
+		memsql.SetFailing(t.Name(), true)
+		defer memsql.SetFailing(t.Name(), false)
+
+		_, err := runCommand(t, "availability", "-driver", memsql.DriverName, "-dsn", t.Name())
+		assert.ErrorIs(t, err, memsql.ErrFailing)
+	})
+
+	t.Run("should require an event file or database", func(t *testing.T) {
+		_, err := runCommand(t, "cancel", "-id", "1")
+		assert.ErrorContains(t, err, "-events or -driver is required")
+
+		_, err = runCommand(t, "cancel", "-id", "1", "-events", "events.json", "-driver", memsql.DriverName)
+		assert.ErrorContains(t, err, "mutually exclusive")
+	})
+}
@@ -0,0 +1,217 @@
+// Package memsql is a database/sql driver that keeps the events table of
+// package sqlstore in memory, so that the store and its users can be tested
+// without a database server. It only understands the statements sqlstore
+// issues. Connections opened with the same name share their data.
+package memsql
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DriverName is the name the driver is registered under.
+const DriverName = "memsql"
+
+// ErrFailing is returned by every statement on a database set to fail.
+var ErrFailing = errors.New("memsql: database is failing")
+
+func init() {
+	sql.Register(DriverName, Driver{})
+}
+
+// database is the data shared by all connections with the same name.
+type database struct {
+	mu      sync.Mutex
+	rows    [][]driver.Value
+	failing bool
+}
+
+var (
+	databasesMu sync.Mutex
+	databases   = make(map[string]*database)
+)
+
+func lookup(name string) *database {
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+
+	db, found := databases[name]
+	if !found {
+		db = &database{}
+		databases[name] = db
+	}
+
+	return db
+}
+
+// SetFailing makes every statement on the named database fail with
+// ErrFailing until it is called again with false.
+func SetFailing(name string, failing bool) {
+	db := lookup(name)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.failing = failing
+}
+
+// Driver opens connections to in-memory databases.
+type Driver struct{}
+
+func (Driver) Open(name string) (driver.Conn, error) {
+	return &conn{db: lookup(name)}, nil
+}
+
+type conn struct {
+	db *database
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{db: c.db, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+// Begin starts a transaction. Statements are applied immediately, so
+// rolling back does not undo them.
+func (c *conn) Begin() (driver.Tx, error) {
+	return tx{}, nil
+}
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+type stmt struct {
+	db    *database
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return strings.Count(s.query, "?")
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), named(args))
+}
+
+func (s *stmt) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if s.db.failing {
+		return nil, ErrFailing
+	}
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "INSERT INTO events"):
+		row := make([]driver.Value, len(args))
+		for i, arg := range args {
+			row[i] = arg.Value
+		}
+		s.db.rows = append(s.db.rows, row)
+
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(s.query, "DELETE FROM events WHERE id = ?"):
+		before := len(s.db.rows)
+		s.db.rows = slices.DeleteFunc(s.db.rows, func(row []driver.Value) bool {
+			return row[0] == args[0].Value
+		})
+
+		return driver.RowsAffected(before - len(s.db.rows)), nil
+	default:
+		return nil, fmt.Errorf("memsql: unsupported statement %q", s.query)
+	}
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), named(args))
+}
+
+func (s *stmt) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if s.db.failing {
+		return nil, ErrFailing
+	}
+
+	switch {
+	case strings.HasPrefix(s.query, "SELECT COUNT(*) FROM events WHERE id = ?"):
+		var n int64
+		for _, row := range s.db.rows {
+			if row[0] == args[0].Value {
+				n++
+			}
+		}
+
+		return &rows{columns: []string{"count"}, rows: [][]driver.Value{{n}}}, nil
+	case strings.HasPrefix(s.query, "SELECT id, kind, starts_at, ends_at, resources, type FROM events"):
+		var selected [][]driver.Value
+		for _, row := range s.db.rows {
+			// With a WHERE clause, the arguments bound starts_at.
+			if len(args) == 2 && (row[2].(int64) < args[0].Value.(int64) || row[2].(int64) >= args[1].Value.(int64)) {
+				continue
+			}
+			selected = append(selected, row)
+		}
+
+		slices.SortStableFunc(selected, func(a, b []driver.Value) int {
+			return cmp.Or(cmp.Compare(a[2].(int64), b[2].(int64)), cmp.Compare(a[0].(int64), b[0].(int64)))
+		})
+
+		return &rows{columns: []string{"id", "kind", "starts_at", "ends_at", "resources", "type"}, rows: selected}, nil
+	default:
+		return nil, fmt.Errorf("memsql: unsupported query %q", s.query)
+	}
+}
+
+type rows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+
+	return nv
+}
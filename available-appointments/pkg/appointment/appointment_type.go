@@ -0,0 +1,248 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Resource is something an appointment needs, e.g. a practitioner or a room.
+// Role groups interchangeable resources, e.g. "doctor", "nurse" or "room".
+type Resource struct {
+	ID   string
+	Role string
+	// Overbooking allows the resource to take part in overlapping
+	// appointments. Nil means every appointment blocks the resource.
+	Overbooking *OverbookingPolicy
+}
+
+// AppointmentType describes a kind of appointment that can be booked, e.g.
+// an initial consultation that takes 45 minutes and needs a doctor and a
+// room.
+type AppointmentType struct {
+	ID       string
+	Name     string
+	Duration time.Duration
+	// RequiredRoles lists one role per resource the appointment occupies.
+	// A role may be listed more than once if several resources of the same
+	// role are needed.
+	RequiredRoles []string
+	// EligibleResources restricts the resources that may serve this type.
+	// An empty list allows every resource with a required role.
+	EligibleResources []string
+}
+
+func (t AppointmentType) isEligible(r Resource) bool {
+	return len(t.EligibleResources) == 0 || slices.Contains(t.EligibleResources, r.ID)
+}
+
+// Catalog holds the appointment types offered.
+type Catalog struct {
+	types map[string]AppointmentType
+	order []string
+}
+
+// NewCatalog creates a catalog from the given types. It fails if a type is
+// incomplete or if an ID is used more than once.
+func NewCatalog(types ...AppointmentType) (*Catalog, error) {
+	c := &Catalog{types: make(map[string]AppointmentType)}
+
+	for _, t := range types {
+		if t.ID == "" {
+			return nil, errors.New("appointment type without ID")
+		}
+		if _, found := c.types[t.ID]; found {
+			return nil, fmt.Errorf("appointment type %q: duplicate ID", t.ID)
+		}
+		if t.Duration <= 0 {
+			return nil, fmt.Errorf("appointment type %q: duration must be positive", t.ID)
+		}
+		if len(t.RequiredRoles) == 0 {
+			return nil, fmt.Errorf("appointment type %q: at least one role is required", t.ID)
+		}
+
+		c.types[t.ID] = t
+		c.order = append(c.order, t.ID)
+	}
+
+	return c, nil
+}
+
+// Get returns the appointment type with the given ID.
+func (c *Catalog) Get(id string) (AppointmentType, bool) {
+	t, found := c.types[id]
+
+	return t, found
+}
+
+// Types returns all appointment types in the order they were added.
+func (c *Catalog) Types() []AppointmentType {
+	types := make([]AppointmentType, 0, len(c.order))
+	for _, id := range c.order {
+		types = append(types, c.types[id])
+	}
+
+	return types
+}
+
+// TypedSlot is a bookable slot for an appointment type. Resources holds the
+// resource assigned to each required role, in the order of the roles.
+type TypedSlot struct {
+	TimeSlot
+	Resources []Resource
+}
+
+// CalculateAvailableSlotsForType returns, for the 7 days starting at
+// startDate, every slot of the type's duration in which a combination of
+// eligible resources covering all required roles is free. Slots are aligned
+// to the start of the free interval they were cut from and sorted by start
+// time. Resources of the same role are tried in the order they are given.
+func CalculateAvailableSlotsForType(db Database, resources []Resource, appointmentType AppointmentType, startDate time.Time) map[string][]TypedSlot {
+	endDate := startDate.AddDate(0, 0, 7)
+	events := db.QueryEvents(startDate, endDate)
+
+	return availableSlotsForType(events, resources, appointmentType, startDate)
+}
+
+func availableSlotsForType(events []Event, resources []Resource, appointmentType AppointmentType, startDate time.Time) map[string][]TypedSlot {
+	results := make(map[string][]TypedSlot)
+
+	currentDate := startDate
+	for range 7 {
+		key := currentDate.Format("2006-01-02")
+		results[key] = []TypedSlot{}
+		currentDate = currentDate.AddDate(0, 0, 1)
+	}
+
+	openings, appointments := filteredEvents(events)
+
+	free := make(map[string][]TimeSlot)
+	for _, r := range resources {
+		free[r.ID] = resourceFreeIntervals(r, appointmentType.ID, openings, appointments)
+	}
+
+	for _, assignment := range resourceAssignments(resources, appointmentType) {
+		intervals := free[assignment[0].ID]
+		for _, r := range assignment[1:] {
+			intervals = intersectIntervals(intervals, free[r.ID])
+		}
+
+		for _, interval := range intervals {
+			slotStart := interval.Start
+			for !slotStart.Add(appointmentType.Duration).After(interval.End) {
+				label := slotStart.Format("2006-01-02")
+				slot := TypedSlot{
+					TimeSlot:  TimeSlot{Start: slotStart, End: slotStart.Add(appointmentType.Duration)},
+					Resources: assignment,
+				}
+				results[label] = append(results[label], slot)
+				slotStart = slot.End
+			}
+		}
+	}
+
+	for label := range results {
+		sort.SliceStable(results[label], func(i, j int) bool {
+			return results[label][i].Start.Before(results[label][j].Start)
+		})
+	}
+
+	return results
+}
+
+// resourceFreeIntervals returns the intervals in which an appointment of the
+// given type can be added for a single resource, sorted by start time.
+func resourceFreeIntervals(r Resource, appointmentType string, openings, appointments []Event) []TimeSlot {
+	var booked []Event
+	for _, a := range appointments {
+		if slices.Contains(a.Resources, r.ID) {
+			booked = append(booked, a)
+		}
+	}
+	booked = blockingAppointments(r, appointmentType, booked)
+
+	var intervals []TimeSlot
+	for _, o := range openings {
+		if slices.Contains(o.Resources, r.ID) {
+			intervals = append(intervals, freeIntervals(o, booked)...)
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	return intervals
+}
+
+// resourceAssignments returns every way of picking distinct, eligible
+// resources for the required roles of the appointment type. Each assignment
+// lists one resource per required role, in the order of the roles.
+func resourceAssignments(resources []Resource, appointmentType AppointmentType) [][]Resource {
+	var assignments [][]Resource
+
+	var assign func(picked []Resource, pickedIndexes []int)
+	assign = func(picked []Resource, pickedIndexes []int) {
+		if len(picked) == len(appointmentType.RequiredRoles) {
+			assignments = append(assignments, slices.Clone(picked))
+			return
+		}
+
+		role := appointmentType.RequiredRoles[len(picked)]
+
+		// When a role is required more than once, only pick resources after
+		// the previously picked one so that every combination shows up once.
+		from := 0
+		for k, p := range picked {
+			if p.Role == role {
+				from = pickedIndexes[k] + 1
+			}
+		}
+
+		for i := from; i < len(resources); i++ {
+			r := resources[i]
+			if r.Role != role || !appointmentType.isEligible(r) || slices.Contains(picked, r) {
+				continue
+			}
+			assign(append(picked, r), append(pickedIndexes, i))
+		}
+	}
+
+	if len(appointmentType.RequiredRoles) > 0 {
+		assign(nil, nil)
+	}
+
+	return assignments
+}
+
+// intersectIntervals returns the overlap of two lists of intervals that are
+// each sorted by start time and free of overlaps.
+func intersectIntervals(a, b []TimeSlot) []TimeSlot {
+	var result []TimeSlot
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start := a[i].Start
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		end := a[i].End
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+
+		if start.Before(end) {
+			result = append(result, TimeSlot{Start: start, End: end})
+		}
+
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return result
+}
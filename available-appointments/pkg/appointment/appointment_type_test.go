@@ -0,0 +1,121 @@
+package appointment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	drSmith = Resource{ID: "dr-smith", Role: "doctor"}
+	drJones = Resource{ID: "dr-jones", Role: "doctor"}
+	nurseLi = Resource{ID: "nurse-li", Role: "nurse"}
+	room1   = Resource{ID: "room-1", Role: "room"}
+
+	clinicResources = []Resource{drSmith, drJones, nurseLi, room1}
+
+	initialConsultation = AppointmentType{ID: "initial", Name: "Initial consultation", Duration: 45 * time.Minute, RequiredRoles: []string{"doctor", "room"}}
+	followUp            = AppointmentType{ID: "follow-up", Name: "Follow-up", Duration: 15 * time.Minute, RequiredRoles: []string{"doctor"}}
+	bloodDraw           = AppointmentType{ID: "blood-draw", Name: "Blood draw", Duration: 10 * time.Minute, RequiredRoles: []string{"nurse"}}
+)
+
+var clinicEvents = []Event{
+	{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T11:00:00.000Z"), Resources: []string{"dr-smith"}},
+	{ID: 2, Kind: KindOpening, StartsAt: parseTime("2025-04-07T10:00:00.000Z"), EndsAt: parseTime("2025-04-07T12:00:00.000Z"), Resources: []string{"room-1"}},
+	{ID: 3, Kind: KindOpening, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:30:00.000Z"), Resources: []string{"nurse-li"}},
+	{ID: 101, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T10:15:00.000Z"), EndsAt: parseTime("2025-04-07T10:30:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+}
+
+func slotStarts(slots []TypedSlot) []string {
+	starts := make([]string, 0, len(slots))
+	for _, s := range slots {
+		starts = append(starts, s.Start.Format("15:04"))
+	}
+
+	return starts
+}
+
+func TestCatalog(t *testing.T) {
+	t.Run("should return types in the order they were added", func(t *testing.T) {
+		catalog, err := NewCatalog(initialConsultation, followUp, bloodDraw)
+		require.NoError(t, err)
+
+		assert.Equal(t, []AppointmentType{initialConsultation, followUp, bloodDraw}, catalog.Types())
+
+		found, ok := catalog.Get("follow-up")
+		assert.True(t, ok)
+		assert.Equal(t, followUp, found)
+	})
+
+	t.Run("should reject duplicate and incomplete types", func(t *testing.T) {
+		_, err := NewCatalog(followUp, followUp)
+		assert.Error(t, err)
+
+		_, err = NewCatalog(AppointmentType{ID: "x", RequiredRoles: []string{"doctor"}})
+		assert.Error(t, err)
+
+		_, err = NewCatalog(AppointmentType{ID: "x", Duration: time.Minute})
+		assert.Error(t, err)
+	})
+}
+
+func TestAvailabilityForType(t *testing.T) {
+	mockDB := new(MockDB)
+
+	startDate := parseTime("2025-04-07T00:00:00.000Z")
+	endDate := parseTime("2025-04-14T00:00:00.000Z")
+
+	t.Run("should only return slots where all required resources are free", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(clinicEvents)
+
+		result := CalculateAvailableSlotsForType(mockDB, clinicResources, initialConsultation, startDate)
+
+		// Dr. Smith is free 9:00-10:15 and 10:30-11:00, the room 10:00-12:00,
+		// so only 10:30-11:00 is shared, which is too short for 45 minutes.
+		assert.Len(t, result, 7)
+		assert.Empty(t, result["2025-04-07"])
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("should cut free intervals into slots of the type's duration", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(clinicEvents)
+
+		result := CalculateAvailableSlotsForType(mockDB, clinicResources, bloodDraw, startDate)
+
+		slots := result["2025-04-07"]
+		assert.Equal(t, []string{"09:00", "09:10", "09:20"}, slotStarts(slots))
+		assert.Equal(t, []Resource{nurseLi}, slots[0].Resources)
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("should leave out resources that are not eligible", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(clinicEvents)
+
+		jonesOnly := followUp
+		jonesOnly.EligibleResources = []string{"dr-jones"}
+
+		result := CalculateAvailableSlotsForType(mockDB, clinicResources, jonesOnly, startDate)
+
+		assert.Empty(t, result["2025-04-07"])
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("should fit appointments around existing bookings", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(clinicEvents)
+
+		result := CalculateAvailableSlotsForType(mockDB, clinicResources, followUp, startDate)
+
+		assert.Equal(t, []string{"09:00", "09:15", "09:30", "09:45", "10:00", "10:30", "10:45"}, slotStarts(result["2025-04-07"]))
+
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -17,6 +17,21 @@ type Database interface {
 func CalculateAvailableSlots(db Database, startDate time.Time) map[string][]TimeSlot {
 	endDate := startDate.AddDate(0, 0, 7)
 	events := db.QueryEvents(startDate, endDate)
+
+	return availableSlots(events, startDate)
+}
+
+// CalculateAvailableSlotsValidated works like CalculateAvailableSlots but
+// validates the queried events first. Invalid events are left out of the
+// calculation and reported through the returned ValidationErrors.
+func CalculateAvailableSlotsValidated(db Database, startDate time.Time) (map[string][]TimeSlot, error) {
+	endDate := startDate.AddDate(0, 0, 7)
+	events, err := QueryValidEvents(db, startDate, endDate)
+
+	return availableSlots(events, startDate), err
+}
+
+func availableSlots(events []Event, startDate time.Time) map[string][]TimeSlot {
 	results := make(map[string][]TimeSlot)
 
 	currentDate := startDate
@@ -29,53 +44,64 @@ func CalculateAvailableSlots(db Database, startDate time.Time) map[string][]Time
 	openings, appointments := filteredEvents(events)
 
 	for _, opening := range openings {
-		openingStart := opening.StartsAt
-		openingEnd := opening.EndsAt
-		openingLabel := openingStart.Format("2006-01-02")
-
-		var overlappingAppointments []Event
-		for _, appointment := range appointments {
-			appointmentStart := appointment.StartsAt
-			appointmentEnd := appointment.EndsAt
-
-			if appointmentEnd.After(openingStart) && (appointmentEnd.Before(openingEnd) || appointmentEnd.Equal(openingEnd)) ||
-				(appointmentStart.After(openingStart) || appointmentStart.Equal(openingStart)) && appointmentStart.Before(openingEnd) ||
-				(appointmentStart.Before(openingStart) || appointmentStart.Equal(openingStart)) && (appointmentEnd.Equal(openingEnd) || appointmentEnd.After(openingEnd)) {
-				overlappingAppointments = append(overlappingAppointments, appointment)
-			}
+		openingLabel := opening.StartsAt.Format("2006-01-02")
+		results[openingLabel] = append(results[openingLabel], freeIntervals(opening, appointments)...)
+	}
+
+	return results
+}
+
+// freeIntervals returns the parts of the opening that are not covered by any
+// of the given appointments.
+func freeIntervals(opening Event, appointments []Event) []TimeSlot {
+	openingStart := opening.StartsAt
+	openingEnd := opening.EndsAt
+
+	var overlappingAppointments []Event
+	for _, appointment := range appointments {
+		appointmentStart := appointment.StartsAt
+		appointmentEnd := appointment.EndsAt
+
+		if appointmentEnd.After(openingStart) && (appointmentEnd.Before(openingEnd) || appointmentEnd.Equal(openingEnd)) ||
+			(appointmentStart.After(openingStart) || appointmentStart.Equal(openingStart)) && appointmentStart.Before(openingEnd) ||
+			(appointmentStart.Before(openingStart) || appointmentStart.Equal(openingStart)) && (appointmentEnd.Equal(openingEnd) || appointmentEnd.After(openingEnd)) {
+			overlappingAppointments = append(overlappingAppointments, appointment)
 		}
+	}
 
-		sort.Slice(overlappingAppointments, func(i, j int) bool {
-			return overlappingAppointments[i].StartsAt.Unix() < overlappingAppointments[j].StartsAt.Unix()
-		})
+	sort.Slice(overlappingAppointments, func(i, j int) bool {
+		return overlappingAppointments[i].StartsAt.Unix() < overlappingAppointments[j].StartsAt.Unix()
+	})
 
-		slotStart := openingStart
-		for _, oa := range overlappingAppointments {
-			oaStart := oa.StartsAt
-			oaEnd := oa.EndsAt
+	// Appointments may overlap, e.g. when a resource is overbooked, so
+	// slotStart is the running maximum end of the appointments seen so far.
+	var slots []TimeSlot
+	slotStart := openingStart
+	for _, oa := range overlappingAppointments {
+		oaStart := oa.StartsAt
+		oaEnd := oa.EndsAt
 
-			if openingStart.Before(oaStart) {
-				timeslot := TimeSlot{Start: slotStart, End: oa.StartsAt}
-				results[openingLabel] = append(results[openingLabel], timeslot)
-			}
+		if slotStart.Before(oaStart) {
+			slots = append(slots, TimeSlot{Start: slotStart, End: oaStart})
+		}
 
+		if oaEnd.After(slotStart) {
 			slotStart = oaEnd
 		}
+	}
 
-		if slotStart.Before(openingEnd) {
-			timeslot := TimeSlot{Start: slotStart, End: openingEnd}
-			results[openingLabel] = append(results[openingLabel], timeslot)
-		}
+	if slotStart.Before(openingEnd) {
+		slots = append(slots, TimeSlot{Start: slotStart, End: openingEnd})
 	}
 
-	return results
+	return slots
 }
 
 func filteredEvents(events []Event) (openings []Event, appointments []Event) {
 	for _, e := range events {
-		if e.Kind == "opening" {
+		if e.Kind == KindOpening {
 			openings = append(openings, e)
-		} else if e.Kind == "appointment" {
+		} else if e.Kind == KindAppointment {
 			appointments = append(appointments, e)
 		}
 	}
@@ -2,9 +2,20 @@ package appointment
 
 import "time"
 
+const (
+	KindOpening     = "opening"
+	KindAppointment = "appointment"
+)
+
 type Event struct {
 	ID       int
 	Kind     string
 	StartsAt time.Time
 	EndsAt   time.Time
+	// Resources lists the IDs of the practitioners and rooms the event
+	// belongs to. Events without resources are only considered by
+	// CalculateAvailableSlots.
+	Resources []string
+	// Type is the ID of the appointment type of an appointment.
+	Type string
 }
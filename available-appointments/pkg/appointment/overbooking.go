@@ -0,0 +1,139 @@
+package appointment
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// OverbookingPolicy allows a resource to take part in several appointments
+// at the same time.
+type OverbookingPolicy struct {
+	// MaxConcurrent is the number of appointments that may overlap at any
+	// point in time. Values below 2 disable overbooking.
+	MaxConcurrent int
+	// AppointmentTypes lists the appointment types that may be overbooked.
+	// An appointment may only overlap others if it and all appointments it
+	// overlaps are of one of these types. Empty means every type.
+	AppointmentTypes []string
+}
+
+func (p *OverbookingPolicy) allows(appointmentType string) bool {
+	if p == nil || p.MaxConcurrent < 2 {
+		return false
+	}
+
+	return len(p.AppointmentTypes) == 0 || slices.Contains(p.AppointmentTypes, appointmentType)
+}
+
+// blockingAppointments returns the intervals in which an appointment of the
+// given type cannot be added to the given appointments of the resource,
+// expressed as events so that they can be passed to freeIntervals. The
+// intervals may overlap.
+func blockingAppointments(r Resource, appointmentType string, appointments []Event) []Event {
+	if !r.Overbooking.allows(appointmentType) {
+		return appointments
+	}
+
+	var blocking, overbookable []Event
+	for _, a := range appointments {
+		if r.Overbooking.allows(a.Type) {
+			overbookable = append(overbookable, a)
+		} else {
+			blocking = append(blocking, a)
+		}
+	}
+
+	// Each interval in which the resource is saturated blocks like an
+	// appointment. It carries the ID of one of the appointments saturating
+	// it, so that conflicts can name an appointment that really overlaps.
+	sweepAppointments(overbookable, func(start, end time.Time, active []Event) {
+		if len(active) >= r.Overbooking.MaxConcurrent {
+			blocking = append(blocking, Event{ID: active[0].ID, Kind: KindAppointment, StartsAt: start, EndsAt: end})
+		}
+	})
+
+	return blocking
+}
+
+// sweepAppointments calls fn for each interval between two consecutive
+// start or end times of the appointments, together with the appointments
+// active during that interval.
+func sweepAppointments(appointments []Event, fn func(start, end time.Time, active []Event)) {
+	var boundaries []time.Time
+	for _, a := range appointments {
+		boundaries = append(boundaries, a.StartsAt, a.EndsAt)
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+	boundaries = slices.CompactFunc(boundaries, time.Time.Equal)
+
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+
+		var active []Event
+		for _, a := range appointments {
+			if a.StartsAt.Before(end) && start.Before(a.EndsAt) {
+				active = append(active, a)
+			}
+		}
+
+		if len(active) > 0 {
+			fn(start, end, active)
+		}
+	}
+}
+
+// OverbookedSlot is a time span in which a resource takes part in more than
+// one appointment.
+type OverbookedSlot struct {
+	TimeSlot
+	Resource       string
+	AppointmentIDs []int
+}
+
+// CalculateOverbookedSlots reports, for the 7 days starting at startDate,
+// every time span in which a resource has overlapping appointments, sorted
+// by resource and start time. Appointments without resources are reported
+// for the resource "".
+func CalculateOverbookedSlots(db Database, startDate time.Time) []OverbookedSlot {
+	endDate := startDate.AddDate(0, 0, 7)
+	_, appointments := filteredEvents(db.QueryEvents(startDate, endDate))
+
+	byResource := make(map[string][]Event)
+	for _, a := range appointments {
+		for _, r := range eventResources(a) {
+			byResource[r] = append(byResource[r], a)
+		}
+	}
+
+	resourceIDs := make([]string, 0, len(byResource))
+	for id := range byResource {
+		resourceIDs = append(resourceIDs, id)
+	}
+	sort.Strings(resourceIDs)
+
+	var overbooked []OverbookedSlot
+	for _, id := range resourceIDs {
+		sweepAppointments(byResource[id], func(start, end time.Time, active []Event) {
+			if len(active) < 2 {
+				return
+			}
+
+			ids := make([]int, 0, len(active))
+			for _, a := range active {
+				ids = append(ids, a.ID)
+			}
+
+			overbooked = append(overbooked, OverbookedSlot{
+				TimeSlot:       TimeSlot{Start: start, End: end},
+				Resource:       id,
+				AppointmentIDs: ids,
+			})
+		})
+	}
+
+	return overbooked
+}
@@ -0,0 +1,108 @@
+package appointment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverbooking(t *testing.T) {
+	mockDB := new(MockDB)
+
+	startDate := parseTime("2025-04-07T00:00:00.000Z")
+	endDate := parseTime("2025-04-14T00:00:00.000Z")
+
+	doubleBooking := &OverbookingPolicy{MaxConcurrent: 2, AppointmentTypes: []string{"follow-up"}}
+	overbookedSmith := Resource{ID: "dr-smith", Role: "doctor", Overbooking: doubleBooking}
+	resources := []Resource{overbookedSmith}
+
+	events := []Event{
+		{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T10:00:00.000Z"), Resources: []string{"dr-smith"}},
+		{ID: 101, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:15:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+		{ID: 102, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:15:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+		{ID: 103, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:15:00.000Z"), EndsAt: parseTime("2025-04-07T09:30:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+		{ID: 104, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:45:00.000Z"), EndsAt: parseTime("2025-04-07T10:00:00.000Z"), Resources: []string{"dr-smith"}, Type: "initial"},
+	}
+
+	t.Run("should offer slots up to the maximum number of concurrent appointments", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(events)
+
+		result := CalculateAvailableSlotsForType(mockDB, resources, followUp, startDate)
+
+		// 09:00-09:15 is full, 09:15-09:30 has room for one more follow-up
+		// and 09:45-10:00 is blocked by an appointment that is not overbookable.
+		assert.Equal(t, []string{"09:15", "09:30"}, slotStarts(result["2025-04-07"]))
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("should not overbook appointment types the policy does not allow", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(events)
+
+		result := CalculateAvailableSlotsForType(mockDB, resources, AppointmentType{ID: "initial", Duration: followUp.Duration, RequiredRoles: []string{"doctor"}}, startDate)
+
+		assert.Equal(t, []string{"09:30"}, slotStarts(result["2025-04-07"]))
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("should respect the policy when booking", func(t *testing.T) {
+		store := NewMemoryStore(events)
+
+		allowed := Event{ID: 200, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:15:00.000Z"), EndsAt: parseTime("2025-04-07T09:30:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"}
+		require.NoError(t, Book(store, resources, allowed))
+
+		full := Event{ID: 201, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:15:00.000Z"), EndsAt: parseTime("2025-04-07T09:30:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"}
+		err := Book(store, resources, full)
+		assert.ErrorIs(t, err, ErrSlotUnavailable)
+		assert.ErrorContains(t, err, "event 103")
+
+		withoutPolicy := Event{ID: 202, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:30:00.000Z"), EndsAt: parseTime("2025-04-07T09:45:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"}
+		require.NoError(t, Book(store, nil, withoutPolicy))
+
+		notOverbookable := Event{ID: 203, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:30:00.000Z"), EndsAt: parseTime("2025-04-07T09:45:00.000Z"), Resources: []string{"dr-smith"}, Type: "initial"}
+		assert.ErrorIs(t, Book(store, resources, notOverbookable), ErrSlotUnavailable)
+	})
+
+	t.Run("should report overbooked slots", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(events)
+
+		overbooked := CalculateOverbookedSlots(mockDB, startDate)
+
+		require.Len(t, overbooked, 1)
+		assert.Equal(t, "dr-smith", overbooked[0].Resource)
+		assert.Equal(t, makeTimeSlot("2025-04-07T09:00:00.000Z", "2025-04-07T09:15:00.000Z"), overbooked[0].TimeSlot)
+		assert.Equal(t, []int{101, 102}, overbooked[0].AppointmentIDs)
+
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("should not offer slots inside nested overlapping appointments", func(t *testing.T) {
+		nested := []Event{
+			{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T10:00:00.000Z"), Resources: []string{"dr-smith"}},
+			{ID: 101, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:45:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+			{ID: 102, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:15:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+		}
+		mockDB.ExpectedCalls = nil
+		mockDB.On("QueryEvents", startDate, endDate).Return(nested)
+
+		initial := AppointmentType{ID: "initial", Duration: followUp.Duration, RequiredRoles: []string{"doctor"}}
+		result := CalculateAvailableSlotsForType(mockDB, resources, initial, startDate)
+		assert.Equal(t, []string{"09:45"}, slotStarts(result["2025-04-07"]))
+
+		free := CalculateAvailableSlots(mockDB, startDate)
+		assert.Equal(t, []TimeSlot{makeTimeSlot("2025-04-07T09:45:00.000Z", "2025-04-07T10:00:00.000Z")}, free["2025-04-07"])
+
+		store := NewMemoryStore(nested)
+		inside := Event{ID: 200, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:15:00.000Z"), EndsAt: parseTime("2025-04-07T09:30:00.000Z"), Resources: []string{"dr-smith"}, Type: "initial"}
+		err := Book(store, resources, inside)
+		assert.ErrorIs(t, err, ErrSlotUnavailable)
+		assert.ErrorContains(t, err, "event 101")
+
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,194 @@
+package appointment
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// DefaultSearchWeeks bounds how far into the future a search looks if the
+// query does not say otherwise.
+const DefaultSearchWeeks = 52
+
+// Ranking orders the candidate slots of one search window in place. load
+// holds the number of appointments per resource ID within that window.
+type Ranking func(slots []TypedSlot, load map[string]int)
+
+// RankEarliest orders slots by start time.
+func RankEarliest(slots []TypedSlot, load map[string]int) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+}
+
+// RankLeastLoaded orders slots by how many appointments their resources
+// already have, so that work is spread evenly. Ties are broken by start time.
+func RankLeastLoaded(slots []TypedSlot, load map[string]int) {
+	slotLoad := func(s TypedSlot) int {
+		total := 0
+		for _, r := range s.Resources {
+			total += load[r.ID]
+		}
+
+		return total
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		li, lj := slotLoad(slots[i]), slotLoad(slots[j])
+		if li != lj {
+			return li < lj
+		}
+
+		return slots[i].Start.Before(slots[j].Start)
+	})
+}
+
+// RankPreferred orders slots served by the given resources first, in the
+// order the resources are listed. Ties are broken by start time.
+func RankPreferred(resourceIDs ...string) Ranking {
+	preference := func(s TypedSlot) int {
+		best := len(resourceIDs)
+		for _, r := range s.Resources {
+			if i := slices.Index(resourceIDs, r.ID); i >= 0 && i < best {
+				best = i
+			}
+		}
+
+		return best
+	}
+
+	return func(slots []TypedSlot, load map[string]int) {
+		sort.SliceStable(slots, func(i, j int) bool {
+			pi, pj := preference(slots[i]), preference(slots[j])
+			if pi != pj {
+				return pi < pj
+			}
+
+			return slots[i].Start.Before(slots[j].Start)
+		})
+	}
+}
+
+// SearchQuery describes the slots a patient is looking for.
+type SearchQuery struct {
+	Type AppointmentType
+	// From is the earliest time a slot may start.
+	From time.Time
+	// Limit is the maximum number of slots returned.
+	Limit int
+	// MaxWeeks bounds how many 7 day windows are searched. Zero means
+	// DefaultSearchWeeks.
+	MaxWeeks int
+	// DayStart and DayEnd restrict slots to a time of day, given as offsets
+	// from midnight. A zero DayEnd means the end of the day.
+	DayStart time.Duration
+	DayEnd   time.Duration
+	// Weekdays restricts slots to the given days. Empty means every day.
+	Weekdays []time.Weekday
+	// Resources restricts slots to those served by at least one of the
+	// given resource IDs. Empty means any resource.
+	Resources []string
+	// Ranking orders the slots of each window. Nil means RankEarliest.
+	Ranking Ranking
+}
+
+func (q SearchQuery) matches(s TypedSlot) bool {
+	if s.Start.Before(q.From) {
+		return false
+	}
+
+	if len(q.Weekdays) > 0 && !slices.Contains(q.Weekdays, s.Start.Weekday()) {
+		return false
+	}
+
+	midnight := time.Date(s.Start.Year(), s.Start.Month(), s.Start.Day(), 0, 0, 0, 0, s.Start.Location())
+	dayEnd := q.DayEnd
+	if dayEnd == 0 {
+		dayEnd = 24 * time.Hour
+	}
+	if s.Start.Sub(midnight) < q.DayStart || s.End.Sub(midnight) > dayEnd {
+		return false
+	}
+
+	if len(q.Resources) > 0 && !slices.ContainsFunc(s.Resources, func(r Resource) bool {
+		return slices.Contains(q.Resources, r.ID)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// SearchSlots returns up to q.Limit slots matching the query. It pages
+// through the database one 7 day window at a time, starting on the day of
+// q.From, until enough slots are found or the horizon is exhausted. The ranking is
+// applied within each window, so slots of an earlier window always come
+// before those of a later one.
+func SearchSlots(db Database, resources []Resource, q SearchQuery) []TypedSlot {
+	maxWeeks := q.MaxWeeks
+	if maxWeeks <= 0 {
+		maxWeeks = DefaultSearchWeeks
+	}
+
+	ranking := q.Ranking
+	if ranking == nil {
+		ranking = RankEarliest
+	}
+
+	// Events only match a window they start in, so the first window starts
+	// at midnight to find openings that are already running at q.From.
+	// Slots before q.From are dropped by q.matches.
+	var found []TypedSlot
+	windowStart := time.Date(q.From.Year(), q.From.Month(), q.From.Day(), 0, 0, 0, 0, q.From.Location())
+	for range maxWeeks {
+		if len(found) >= q.Limit {
+			break
+		}
+
+		windowEnd := windowStart.AddDate(0, 0, 7)
+		events := db.QueryEvents(windowStart, windowEnd)
+
+		slotsByDay := availableSlotsForType(events, resources, q.Type, windowStart)
+		days := make([]string, 0, len(slotsByDay))
+		for day := range slotsByDay {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+
+		var candidates []TypedSlot
+		for _, day := range days {
+			for _, s := range slotsByDay[day] {
+				if q.matches(s) {
+					candidates = append(candidates, s)
+				}
+			}
+		}
+
+		ranking(candidates, appointmentLoad(events))
+
+		remaining := q.Limit - len(found)
+		if len(candidates) > remaining {
+			candidates = candidates[:remaining]
+		}
+		found = append(found, candidates...)
+
+		windowStart = windowEnd
+	}
+
+	return found
+}
+
+// appointmentLoad counts the appointments per resource ID.
+func appointmentLoad(events []Event) map[string]int {
+	load := make(map[string]int)
+	for _, e := range events {
+		if e.Kind != KindAppointment {
+			continue
+		}
+		for _, r := range e.Resources {
+			load[r]++
+		}
+	}
+
+	return load
+}
@@ -0,0 +1,101 @@
+package appointment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventDB answers queries from a fixed list of events, like a real database
+// would, so that searches can page through several windows.
+type eventDB struct {
+	events  []Event
+	queries int
+}
+
+func (db *eventDB) QueryEvents(startDate, endDate time.Time) []Event {
+	db.queries++
+
+	return filterEvents(db.events, startDate, endDate)
+}
+
+var searchEvents = []Event{
+	// Monday, 2025-04-07
+	{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-04-07T08:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:00:00.000Z"), Resources: []string{"dr-smith"}},
+	{ID: 2, Kind: KindOpening, StartsAt: parseTime("2025-04-07T14:00:00.000Z"), EndsAt: parseTime("2025-04-07T15:00:00.000Z"), Resources: []string{"dr-jones"}},
+	{ID: 101, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T08:00:00.000Z"), EndsAt: parseTime("2025-04-07T08:30:00.000Z"), Resources: []string{"dr-smith"}, Type: "follow-up"},
+	// Wednesday, four weeks later
+	{ID: 3, Kind: KindOpening, StartsAt: parseTime("2025-05-07T09:00:00.000Z"), EndsAt: parseTime("2025-05-07T09:30:00.000Z"), Resources: []string{"dr-jones"}},
+}
+
+func TestSearchSlots(t *testing.T) {
+	from := parseTime("2025-04-07T00:00:00.000Z")
+
+	t.Run("should return the earliest slots first", func(t *testing.T) {
+		db := &eventDB{events: searchEvents}
+
+		slots := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 3})
+
+		require.Len(t, slots, 3)
+		assert.Equal(t, "2025-04-07T08:30:00Z", formatTime(slots[0].Start))
+		assert.Equal(t, "2025-04-07T08:45:00Z", formatTime(slots[1].Start))
+		assert.Equal(t, "2025-04-07T14:00:00Z", formatTime(slots[2].Start))
+		assert.Equal(t, 1, db.queries)
+	})
+
+	t.Run("should page through windows until enough slots are found", func(t *testing.T) {
+		db := &eventDB{events: searchEvents}
+
+		slots := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 1, Weekdays: []time.Weekday{time.Wednesday}})
+
+		require.Len(t, slots, 1)
+		assert.Equal(t, "2025-05-07T09:00:00Z", formatTime(slots[0].Start))
+		assert.Equal(t, 5, db.queries)
+	})
+
+	t.Run("should find slots in openings that are already running", func(t *testing.T) {
+		store := NewMemoryStore(searchEvents)
+
+		slots := SearchSlots(store, clinicResources, SearchQuery{Type: followUp, From: parseTime("2025-04-07T14:20:00.000Z"), Limit: 2})
+
+		require.Len(t, slots, 2)
+		assert.Equal(t, "2025-04-07T14:30:00Z", formatTime(slots[0].Start))
+		assert.Equal(t, "2025-04-07T14:45:00Z", formatTime(slots[1].Start))
+	})
+
+	t.Run("should stop at the search horizon", func(t *testing.T) {
+		db := &eventDB{events: searchEvents}
+
+		slots := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 10, MaxWeeks: 2})
+
+		assert.Len(t, slots, 6)
+		assert.Equal(t, 2, db.queries)
+	})
+
+	t.Run("should filter by time of day and resource", func(t *testing.T) {
+		db := &eventDB{events: searchEvents}
+
+		morning := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 10, MaxWeeks: 1, DayEnd: 12 * time.Hour})
+		assert.Len(t, morning, 2)
+
+		withJones := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 10, MaxWeeks: 1, Resources: []string{"dr-jones"}})
+		assert.Len(t, withJones, 4)
+		for _, s := range withJones {
+			assert.Equal(t, []Resource{drJones}, s.Resources)
+		}
+	})
+
+	t.Run("should rank by preferred practitioner and by load", func(t *testing.T) {
+		db := &eventDB{events: searchEvents}
+
+		preferred := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 1, Ranking: RankPreferred("dr-jones")})
+		require.Len(t, preferred, 1)
+		assert.Equal(t, "2025-04-07T14:00:00Z", formatTime(preferred[0].Start))
+
+		leastLoaded := SearchSlots(db, clinicResources, SearchQuery{Type: followUp, From: from, Limit: 1, Ranking: RankLeastLoaded})
+		require.Len(t, leastLoaded, 1)
+		assert.Equal(t, []Resource{drJones}, leastLoaded[0].Resources)
+	})
+}
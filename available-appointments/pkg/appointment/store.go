@@ -0,0 +1,170 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrNoOpening        = errors.New("no opening covers the requested time")
+	ErrSlotUnavailable  = errors.New("requested time overlaps another appointment")
+	ErrNotAnAppointment = errors.New("event is not an appointment")
+)
+
+// Store is a Database whose events can be changed.
+type Store interface {
+	Database
+	// Events returns all events of the store.
+	Events() []Event
+	AddEvent(e Event) error
+	RemoveEvent(id int) error
+}
+
+// MemoryStore keeps events in memory. It is not safe for concurrent use.
+type MemoryStore struct {
+	events []Event
+}
+
+func NewMemoryStore(events []Event) *MemoryStore {
+	return &MemoryStore{events: slices.Clone(events)}
+}
+
+// QueryEvents returns the events starting within [startDate, endDate).
+func (s *MemoryStore) QueryEvents(startDate, endDate time.Time) []Event {
+	var events []Event
+	for _, e := range s.events {
+		if !e.StartsAt.Before(startDate) && e.StartsAt.Before(endDate) {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// Events returns all events sorted by start time.
+func (s *MemoryStore) Events() []Event {
+	events := slices.Clone(s.events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StartsAt.Before(events[j].StartsAt)
+	})
+
+	return events
+}
+
+// AddEvent validates the event and adds it to the store.
+func (s *MemoryStore) AddEvent(e Event) error {
+	errs := ValidateEvent(e)
+	for _, existing := range s.events {
+		if existing.ID == e.ID {
+			errs = append(errs, ValidationError{EventID: e.ID, Field: "ID", Reason: "duplicate ID"})
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	s.events = append(s.events, e)
+
+	return nil
+}
+
+// RemoveEvent removes the event with the given ID.
+func (s *MemoryStore) RemoveEvent(id int) error {
+	for i, e := range s.events {
+		if e.ID == id {
+			s.events = slices.Delete(s.events, i, i+1)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("event %d: %w", id, ErrEventNotFound)
+}
+
+// NextID returns an ID that is not used by any event of the store.
+func NextID(store Store) int {
+	maxID := 0
+	for _, e := range store.Events() {
+		maxID = max(maxID, e.ID)
+	}
+
+	return maxID + 1
+}
+
+// Book adds the appointment to the store if every one of its resources has
+// an opening covering it and no overlapping appointment, unless the
+// resource's overbooking policy allows the overlap. Policies are looked up
+// in the given resources. Appointments without resources are checked
+// against the openings and appointments that have no resources either.
+func Book(store Store, resources []Resource, booking Event) error {
+	events := store.Events()
+	if err := ValidateBooking(events, booking); err != nil {
+		return err
+	}
+
+	openings, appointments := filteredEvents(events)
+
+	for _, resource := range eventResources(booking) {
+		covered := slices.ContainsFunc(openings, func(o Event) bool {
+			return slices.Contains(eventResources(o), resource) &&
+				!o.StartsAt.After(booking.StartsAt) && !o.EndsAt.Before(booking.EndsAt)
+		})
+		if !covered {
+			return fmt.Errorf("event %d: %w", booking.ID, ErrNoOpening)
+		}
+
+		r := Resource{ID: resource}
+		if i := slices.IndexFunc(resources, func(r Resource) bool { return r.ID == resource }); i >= 0 {
+			r = resources[i]
+		}
+
+		var booked []Event
+		for _, a := range appointments {
+			if slices.Contains(eventResources(a), resource) {
+				booked = append(booked, a)
+			}
+		}
+
+		for _, a := range blockingAppointments(r, booking.Type, booked) {
+			if overlaps(a, booking) {
+				return fmt.Errorf("event %d: %w: event %d", booking.ID, ErrSlotUnavailable, a.ID)
+			}
+		}
+	}
+
+	return store.AddEvent(booking)
+}
+
+// Cancel removes the appointment with the given ID from the store.
+func Cancel(store Store, id int) error {
+	for _, e := range store.Events() {
+		if e.ID != id {
+			continue
+		}
+		if e.Kind != KindAppointment {
+			return fmt.Errorf("event %d: %w", id, ErrNotAnAppointment)
+		}
+
+		return store.RemoveEvent(id)
+	}
+
+	return fmt.Errorf("event %d: %w", id, ErrEventNotFound)
+}
+
+// eventResources returns the resources of an event. Events without
+// resources belong to a single unnamed resource.
+func eventResources(e Event) []string {
+	if len(e.Resources) == 0 {
+		return []string{""}
+	}
+
+	return e.Resources
+}
+
+func overlaps(a, b Event) bool {
+	return a.StartsAt.Before(b.EndsAt) && b.StartsAt.Before(a.EndsAt)
+}
@@ -0,0 +1,55 @@
+package appointment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBooking(t *testing.T) {
+	newStore := func() *MemoryStore {
+		return NewMemoryStore(clinicEvents)
+	}
+
+	t.Run("should book an appointment inside an opening", func(t *testing.T) {
+		store := newStore()
+		booking := Event{ID: NextID(store), Kind: KindAppointment, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T09:15:00.000Z"), Resources: []string{"dr-smith"}}
+
+		require.NoError(t, Book(store, clinicResources, booking))
+
+		assert.Equal(t, 102, booking.ID)
+		assert.Contains(t, store.Events(), booking)
+	})
+
+	t.Run("should reject appointments outside of openings", func(t *testing.T) {
+		store := newStore()
+		booking := Event{ID: 200, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T10:45:00.000Z"), EndsAt: parseTime("2025-04-07T11:15:00.000Z"), Resources: []string{"dr-smith"}}
+
+		assert.ErrorIs(t, Book(store, clinicResources, booking), ErrNoOpening)
+	})
+
+	t.Run("should reject appointments overlapping another appointment", func(t *testing.T) {
+		store := newStore()
+		booking := Event{ID: 200, Kind: KindAppointment, StartsAt: parseTime("2025-04-07T10:00:00.000Z"), EndsAt: parseTime("2025-04-07T10:30:00.000Z"), Resources: []string{"dr-smith"}}
+
+		assert.ErrorIs(t, Book(store, clinicResources, booking), ErrSlotUnavailable)
+	})
+
+	t.Run("should check resource-less appointments against resource-less openings", func(t *testing.T) {
+		store := NewMemoryStore(mockEvents)
+		booking := Event{ID: 200, Kind: KindAppointment, StartsAt: parseTime("2025-03-30T09:00:00.000Z"), EndsAt: parseTime("2025-03-30T09:30:00.000Z")}
+
+		assert.NoError(t, Book(store, clinicResources, booking))
+	})
+
+	t.Run("should cancel appointments but not openings", func(t *testing.T) {
+		store := newStore()
+
+		assert.ErrorIs(t, Cancel(store, 1), ErrNotAnAppointment)
+		assert.ErrorIs(t, Cancel(store, 999), ErrEventNotFound)
+
+		require.NoError(t, Cancel(store, 101))
+		assert.Len(t, store.Events(), len(clinicEvents)-1)
+	})
+}
@@ -0,0 +1,138 @@
+package appointment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidationError describes a single problem with a single event.
+type ValidationError struct {
+	EventID int
+	Field   string
+	Reason  string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("event %d: %s: %s", e.EventID, e.Field, e.Reason)
+}
+
+// ValidationErrors collects all problems found while validating a set of
+// events so that callers can report them at once.
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// ForEvent returns the problems reported for the event with the given ID.
+func (errs ValidationErrors) ForEvent(id int) ValidationErrors {
+	var filtered ValidationErrors
+	for _, e := range errs {
+		if e.EventID == id {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
+
+// ValidateEvent checks a single event in isolation.
+func ValidateEvent(e Event) ValidationErrors {
+	var errs ValidationErrors
+	invalid := func(field, reason string) {
+		errs = append(errs, ValidationError{EventID: e.ID, Field: field, Reason: reason})
+	}
+
+	if e.ID <= 0 {
+		invalid("ID", "must be positive")
+	}
+
+	if e.Kind != KindOpening && e.Kind != KindAppointment {
+		invalid("Kind", fmt.Sprintf("unknown kind %q", e.Kind))
+	}
+
+	if e.StartsAt.IsZero() {
+		invalid("StartsAt", "must be set")
+	}
+
+	if e.EndsAt.IsZero() {
+		invalid("EndsAt", "must be set")
+	}
+
+	if !e.StartsAt.IsZero() && !e.EndsAt.IsZero() && !e.EndsAt.After(e.StartsAt) {
+		invalid("EndsAt", "must be after StartsAt")
+	}
+
+	return errs
+}
+
+// ValidateEvents checks every event and additionally reports IDs that are
+// used more than once. The first event with a given ID is considered the
+// original, every following one is reported as a duplicate.
+func ValidateEvents(events []Event) ValidationErrors {
+	_, errs := partitionEvents(events)
+
+	return errs
+}
+
+// ValidateBooking checks an appointment that is about to be added to the
+// given existing events.
+func ValidateBooking(existing []Event, booking Event) error {
+	errs := ValidateEvent(booking)
+
+	if booking.Kind != KindAppointment {
+		errs = append(errs, ValidationError{EventID: booking.ID, Field: "Kind", Reason: "only appointments can be booked"})
+	}
+
+	for _, e := range existing {
+		if e.ID == booking.ID {
+			errs = append(errs, ValidationError{EventID: booking.ID, Field: "ID", Reason: "duplicate ID"})
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// QueryValidEvents queries the database and splits the result into events
+// that are safe to use and the problems found with the rest. The returned
+// error is either nil or of type ValidationErrors.
+func QueryValidEvents(db Database, startDate, endDate time.Time) ([]Event, error) {
+	valid, errs := partitionEvents(db.QueryEvents(startDate, endDate))
+	if len(errs) > 0 {
+		return valid, errs
+	}
+
+	return valid, nil
+}
+
+func partitionEvents(events []Event) (valid []Event, errs ValidationErrors) {
+	seen := make(map[int]bool)
+
+	for _, e := range events {
+		eventErrs := ValidateEvent(e)
+		if seen[e.ID] {
+			eventErrs = append(eventErrs, ValidationError{EventID: e.ID, Field: "ID", Reason: "duplicate ID"})
+		}
+		seen[e.ID] = true
+
+		if len(eventErrs) > 0 {
+			errs = append(errs, eventErrs...)
+			continue
+		}
+
+		valid = append(valid, e)
+	}
+
+	return valid, errs
+}
@@ -0,0 +1,94 @@
+package appointment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventValidation(t *testing.T) {
+	t.Run("should accept a well-formed event", func(t *testing.T) {
+		event := Event{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-03-30T09:00:00.000Z"), EndsAt: parseTime("2025-03-30T12:00:00.000Z")}
+
+		assert.Empty(t, ValidateEvent(event))
+	})
+
+	t.Run("should report events that end before they start", func(t *testing.T) {
+		event := Event{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-03-30T12:00:00.000Z"), EndsAt: parseTime("2025-03-30T09:00:00.000Z")}
+
+		errs := ValidateEvent(event)
+
+		require.Len(t, errs, 1)
+		assert.Equal(t, ValidationError{EventID: 1, Field: "EndsAt", Reason: "must be after StartsAt"}, errs[0])
+	})
+
+	t.Run("should report zero timestamps, unknown kinds and missing IDs", func(t *testing.T) {
+		errs := ValidateEvent(Event{Kind: "holiday"})
+
+		fields := make([]string, 0, len(errs))
+		for _, e := range errs {
+			fields = append(fields, e.Field)
+		}
+
+		assert.ElementsMatch(t, []string{"ID", "Kind", "StartsAt", "EndsAt"}, fields)
+	})
+
+	t.Run("should report duplicate IDs once per duplicate", func(t *testing.T) {
+		events := []Event{
+			{ID: 1, Kind: KindOpening, StartsAt: parseTime("2025-03-30T09:00:00.000Z"), EndsAt: parseTime("2025-03-30T12:00:00.000Z")},
+			{ID: 1, Kind: KindAppointment, StartsAt: parseTime("2025-03-30T10:00:00.000Z"), EndsAt: parseTime("2025-03-30T10:30:00.000Z")},
+		}
+
+		errs := ValidateEvents(events)
+
+		require.Len(t, errs, 1)
+		assert.Equal(t, "event 1: ID: duplicate ID", errs[0].Error())
+	})
+
+	t.Run("should reject bookings that are not appointments or reuse IDs", func(t *testing.T) {
+		booking := Event{ID: 101, Kind: KindOpening, StartsAt: parseTime("2025-03-30T10:00:00.000Z"), EndsAt: parseTime("2025-03-30T10:30:00.000Z")}
+
+		err := ValidateBooking(mockEvents, booking)
+
+		var errs ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs.ForEvent(101), 2)
+	})
+
+	t.Run("should accept a valid booking", func(t *testing.T) {
+		booking := Event{ID: 200, Kind: KindAppointment, StartsAt: parseTime("2025-03-30T09:00:00.000Z"), EndsAt: parseTime("2025-03-30T09:30:00.000Z")}
+
+		assert.NoError(t, ValidateBooking(mockEvents, booking))
+	})
+}
+
+func TestValidatedAvailability(t *testing.T) {
+	mockDB := new(MockDB)
+
+	t.Run("should leave out invalid events and report them", func(t *testing.T) {
+		mockDB.ExpectedCalls = nil
+
+		startDate := parseTime("2025-04-06T00:00:00.000Z")
+		endDate := startDate.Add(7 * 24 * time.Hour)
+
+		events := []Event{
+			{ID: 10, Kind: KindOpening, StartsAt: parseTime("2025-04-06T10:00:00.000Z"), EndsAt: parseTime("2025-04-06T12:00:00.000Z")},
+			{ID: 110, Kind: KindAppointment, StartsAt: parseTime("2025-04-06T11:00:00.000Z"), EndsAt: parseTime("2025-04-06T10:00:00.000Z")},
+		}
+
+		mockDB.On("QueryEvents", startDate, endDate).Return(events)
+
+		result, err := CalculateAvailableSlotsValidated(mockDB, startDate)
+
+		var errs ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		assert.Len(t, errs.ForEvent(110), 1)
+
+		require.Len(t, result["2025-04-06"], 1)
+		assert.Equal(t, makeTimeSlot("2025-04-06T10:00:00.000Z", "2025-04-06T12:00:00.000Z"), result["2025-04-06"][0])
+
+		mockDB.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,89 @@
+package eventfile
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+// csvHeader lists the columns of an event CSV file. Multiple resources are
+// separated by semicolons.
+var csvHeader = []string{"id", "kind", "starts_at", "ends_at", "resources", "type"}
+
+func readCSV(r io.Reader) ([]appointment.Event, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var events []appointment.Event
+	for i, rec := range records[1:] {
+		line := i + 2
+
+		id, err := strconv.Atoi(rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid id: %w", line, err)
+		}
+		startsAt, err := time.Parse(time.RFC3339, rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid starts_at: %w", line, err)
+		}
+		endsAt, err := time.Parse(time.RFC3339, rec[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid ends_at: %w", line, err)
+		}
+
+		var resources []string
+		if rec[4] != "" {
+			resources = strings.Split(rec[4], ";")
+		}
+
+		events = append(events, appointment.Event{
+			ID:        id,
+			Kind:      rec[1],
+			StartsAt:  startsAt,
+			EndsAt:    endsAt,
+			Resources: resources,
+			Type:      rec[5],
+		})
+	}
+
+	return events, nil
+}
+
+func writeCSV(w io.Writer, events []appointment.Event) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		rec := []string{
+			strconv.Itoa(e.ID),
+			e.Kind,
+			e.StartsAt.Format(time.RFC3339),
+			e.EndsAt.Format(time.RFC3339),
+			strings.Join(e.Resources, ";"),
+			e.Type,
+		}
+		if err := writer.Write(rec); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
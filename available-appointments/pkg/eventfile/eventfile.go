@@ -0,0 +1,102 @@
+// Package eventfile reads and writes appointment events as JSON, CSV or
+// iCalendar (ICS) files.
+package eventfile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatICS  Format = "ics"
+)
+
+// FormatFromPath derives the format from the file extension.
+func FormatFromPath(path string) (Format, error) {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "ics", "ical":
+		return FormatICS, nil
+	default:
+		return "", fmt.Errorf("unsupported event file extension %q", ext)
+	}
+}
+
+// Read decodes events in the given format.
+func Read(r io.Reader, format Format) ([]appointment.Event, error) {
+	switch format {
+	case FormatJSON:
+		return readJSON(r)
+	case FormatCSV:
+		return readCSV(r)
+	case FormatICS:
+		return readICS(r)
+	default:
+		return nil, fmt.Errorf("unsupported event format %q", format)
+	}
+}
+
+// Write encodes events in the given format.
+func Write(w io.Writer, format Format, events []appointment.Event) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, events)
+	case FormatCSV:
+		return writeCSV(w, events)
+	case FormatICS:
+		return writeICS(w, events)
+	default:
+		return fmt.Errorf("unsupported event format %q", format)
+	}
+}
+
+// ReadFile reads the events of a file, deriving the format from its
+// extension.
+func ReadFile(path string) ([]appointment.Event, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	events, err := Read(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return events, nil
+}
+
+// WriteFile replaces the contents of a file with the given events, deriving
+// the format from its extension.
+func WriteFile(path string, events []appointment.Event) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, format, events); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
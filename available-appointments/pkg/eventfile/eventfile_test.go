@@ -0,0 +1,102 @@
+package eventfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+func parseTime(timeStr string) time.Time {
+	t, _ := time.Parse(time.RFC3339, timeStr)
+
+	return t
+}
+
+var events = []appointment.Event{
+	{ID: 1, Kind: appointment.KindOpening, StartsAt: parseTime("2025-04-07T09:00:00.000Z"), EndsAt: parseTime("2025-04-07T11:00:00.000Z"), Resources: []string{"dr-smith", "room-1"}},
+	{ID: 101, Kind: appointment.KindAppointment, StartsAt: parseTime("2025-04-07T10:15:00.000Z"), EndsAt: parseTime("2025-04-07T10:30:00.000Z"), Type: "follow-up"},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatCSV, FormatICS} {
+		t.Run("should read back what was written as "+string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, format, events))
+
+			read, err := Read(&buf, format)
+			require.NoError(t, err)
+
+			assert.Equal(t, events, read)
+		})
+	}
+}
+
+func TestReadICS(t *testing.T) {
+	t.Run("should read folded lines, time zones and foreign events", func(t *testing.T) {
+		calendar := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:7@example.com",
+			"DTSTART;TZID=Europe/Berlin:20250407T090000",
+			"DTEND;TZID=Europe/Berlin:20250407T093000",
+			"X-APPOINTMENT-RESOURCES:dr-smith,",
+			" room-1",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+
+		read, err := Read(strings.NewReader(calendar), FormatICS)
+		require.NoError(t, err)
+		require.Len(t, read, 1)
+
+		assert.Equal(t, 7, read[0].ID)
+		assert.Equal(t, appointment.KindAppointment, read[0].Kind)
+		assert.Equal(t, "2025-04-07T07:00:00Z", read[0].StartsAt.UTC().Format(time.RFC3339))
+		assert.Equal(t, []string{"dr-smith", "room-1"}, read[0].Resources)
+	})
+
+	t.Run("should number events without a numeric UID after the others", func(t *testing.T) {
+		calendar := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:040000008200E00074C5B7101A82E008@outlook.com",
+			"DTSTART:20250407T090000Z",
+			"DTEND:20250407T093000Z",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:12@appointment-system",
+			"DTSTART:20250407T100000Z",
+			"DTEND:20250407T103000Z",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:6f1c2e4a-3b5d-4e8f-9a7b-2c1d0e9f8a7b",
+			"DTSTART:20250407T110000Z",
+			"DTEND:20250407T113000Z",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+
+		read, err := Read(strings.NewReader(calendar), FormatICS)
+		require.NoError(t, err)
+		require.Len(t, read, 3)
+
+		assert.Equal(t, 13, read[0].ID)
+		assert.Equal(t, 12, read[1].ID)
+		assert.Equal(t, 14, read[2].ID)
+	})
+}
+
+func TestFormatFromPath(t *testing.T) {
+	format, err := FormatFromPath("calendar.ICS")
+	require.NoError(t, err)
+	assert.Equal(t, FormatICS, format)
+
+	_, err = FormatFromPath("calendar.txt")
+	assert.Error(t, err)
+}
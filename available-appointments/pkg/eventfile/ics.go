@@ -0,0 +1,194 @@
+package eventfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+const (
+	icsDateTimeUTC = "20060102T150405Z"
+	icsDateTime    = "20060102T150405"
+	icsDate        = "20060102"
+
+	// Non-standard properties carrying the fields iCalendar has no place for.
+	icsKindProperty      = "X-APPOINTMENT-KIND"
+	icsResourcesProperty = "X-APPOINTMENT-RESOURCES"
+	icsTypeProperty      = "X-APPOINTMENT-TYPE"
+)
+
+// readICS reads the VEVENTs of a calendar. The event ID is taken from the
+// numeric part of the UID before an optional "@". Events with other UIDs,
+// such as the UUIDs of foreign calendars, are numbered after the highest
+// such ID. Events without a kind are treated as appointments, so that
+// foreign calendars block time.
+func readICS(r io.Reader) ([]appointment.Event, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []appointment.Event
+	var current *appointment.Event
+	// foreign are the indices of the events without a numeric UID.
+	var foreign []int
+
+	for i, line := range lines {
+		name, params, value, ok := parseICSLine(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: malformed content line", i+1)
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &appointment.Event{Kind: appointment.KindAppointment}
+		case name == "END" && value == "VEVENT":
+			if current != nil {
+				if current.ID == 0 {
+					foreign = append(foreign, len(events))
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			if id, err := strconv.Atoi(strings.SplitN(value, "@", 2)[0]); err == nil && id > 0 {
+				current.ID = id
+			}
+		case name == "DTSTART" || name == "DTEND":
+			t, err := parseICSTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", i+1, name, err)
+			}
+			if name == "DTSTART" {
+				current.StartsAt = t
+			} else {
+				current.EndsAt = t
+			}
+		case name == icsKindProperty:
+			current.Kind = value
+		case name == icsResourcesProperty:
+			if value != "" {
+				current.Resources = strings.Split(value, ",")
+			}
+		case name == icsTypeProperty:
+			current.Type = value
+		}
+	}
+
+	nextID := appointment.NextID(appointment.NewMemoryStore(events))
+	for _, i := range foreign {
+		events[i].ID = nextID
+		nextID++
+	}
+
+	return events, nil
+}
+
+func writeICS(w io.Writer, events []appointment.Event) error {
+	bw := bufio.NewWriter(w)
+
+	writeLine := func(line string) {
+		// Lines longer than 75 octets are folded as required by RFC 5545.
+		for len(line) > 75 {
+			bw.WriteString(line[:75] + "\r\n")
+			line = " " + line[75:]
+		}
+		bw.WriteString(line + "\r\n")
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//baschtl//appointment-system//EN")
+
+	for _, e := range events {
+		summary := e.Kind
+		if e.Type != "" {
+			summary += " " + e.Type
+		}
+
+		writeLine("BEGIN:VEVENT")
+		writeLine(fmt.Sprintf("UID:%d@appointment-system", e.ID))
+		writeLine("DTSTAMP:" + e.StartsAt.UTC().Format(icsDateTimeUTC))
+		writeLine("DTSTART:" + e.StartsAt.UTC().Format(icsDateTimeUTC))
+		writeLine("DTEND:" + e.EndsAt.UTC().Format(icsDateTimeUTC))
+		writeLine("SUMMARY:" + summary)
+		writeLine(icsKindProperty + ":" + e.Kind)
+		if len(e.Resources) > 0 {
+			writeLine(icsResourcesProperty + ":" + strings.Join(e.Resources, ","))
+		}
+		if e.Type != "" {
+			writeLine(icsTypeProperty + ":" + e.Type)
+		}
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// unfoldICS splits the input into content lines, joining folded lines.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// parseICSLine splits a content line of the form NAME;PARAM=VALUE:VALUE.
+func parseICSLine(line string) (name string, params map[string]string, value string, ok bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(head, ";")
+	params = make(map[string]string)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+func parseICSTime(value string, params map[string]string) (time.Time, error) {
+	if params["VALUE"] == "DATE" {
+		return time.Parse(icsDate, value)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icsDateTimeUTC, value)
+	}
+
+	loc := time.UTC
+	if tzid, found := params["TZID"]; found {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.ParseInLocation(icsDateTime, value, loc)
+}
@@ -0,0 +1,58 @@
+package eventfile
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+type jsonEvent struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Resources []string  `json:"resources,omitempty"`
+	Type      string    `json:"type,omitempty"`
+}
+
+func readJSON(r io.Reader) ([]appointment.Event, error) {
+	var records []jsonEvent
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	events := make([]appointment.Event, 0, len(records))
+	for _, rec := range records {
+		events = append(events, appointment.Event{
+			ID:        rec.ID,
+			Kind:      rec.Kind,
+			StartsAt:  rec.StartsAt,
+			EndsAt:    rec.EndsAt,
+			Resources: rec.Resources,
+			Type:      rec.Type,
+		})
+	}
+
+	return events, nil
+}
+
+func writeJSON(w io.Writer, events []appointment.Event) error {
+	records := make([]jsonEvent, 0, len(events))
+	for _, e := range events {
+		records = append(records, jsonEvent{
+			ID:        e.ID,
+			Kind:      e.Kind,
+			StartsAt:  e.StartsAt,
+			EndsAt:    e.EndsAt,
+			Resources: e.Resources,
+			Type:      e.Type,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(records)
+}
@@ -0,0 +1,178 @@
+// Package sqlstore keeps appointment events in a SQL database through
+// database/sql. The driver is chosen by the caller; statements use "?"
+// placeholders, as understood by SQLite and MySQL.
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baschtl/appointment-system/pkg/appointment"
+)
+
+// Schema creates the table the store keeps its events in. Times are stored
+// as Unix seconds and resources as a comma separated list.
+const Schema = `CREATE TABLE IF NOT EXISTS events (
+	id INTEGER PRIMARY KEY,
+	kind TEXT NOT NULL,
+	starts_at BIGINT NOT NULL,
+	ends_at BIGINT NOT NULL,
+	resources TEXT NOT NULL,
+	type TEXT NOT NULL
+)`
+
+const (
+	selectEvents  = "SELECT id, kind, starts_at, ends_at, resources, type FROM events"
+	orderEvents   = " ORDER BY starts_at, id"
+	queryRange    = selectEvents + " WHERE starts_at >= ? AND starts_at < ?" + orderEvents
+	queryAll      = selectEvents + orderEvents
+	countID       = "SELECT COUNT(*) FROM events WHERE id = ?"
+	insertEvent   = "INSERT INTO events (id, kind, starts_at, ends_at, resources, type) VALUES (?, ?, ?, ?, ?, ?)"
+	deleteEventID = "DELETE FROM events WHERE id = ?"
+)
+
+// Store is an appointment.Store backed by a SQL database.
+//
+// The appointment.Database interface cannot report errors, so QueryEvents
+// and Events return no events if the database fails. The first such error
+// is kept and returned by Err.
+//
+// Store is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+
+	mu  sync.Mutex
+	err error
+}
+
+var _ appointment.Store = (*Store)(nil)
+
+// New returns a store that keeps its events in db, creating the events
+// table if it does not exist yet.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(Schema); err != nil {
+		return nil, fmt.Errorf("create events table: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Err returns the first error that QueryEvents or Events ran into.
+func (s *Store) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+func (s *Store) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// QueryEvents returns the events starting within [startDate, endDate).
+func (s *Store) QueryEvents(startDate, endDate time.Time) []appointment.Event {
+	events, err := s.query(queryRange, startDate.Unix(), endDate.Unix())
+	if err != nil {
+		s.fail(fmt.Errorf("query events: %w", err))
+		return nil
+	}
+
+	return events
+}
+
+// Events returns all events sorted by start time.
+func (s *Store) Events() []appointment.Event {
+	events, err := s.query(queryAll)
+	if err != nil {
+		s.fail(fmt.Errorf("query events: %w", err))
+		return nil
+	}
+
+	return events
+}
+
+// AddEvent validates the event and adds it to the store.
+func (s *Store) AddEvent(e appointment.Event) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	errs := appointment.ValidateEvent(e)
+
+	var n int
+	if err := tx.QueryRow(countID, e.ID).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		errs = append(errs, appointment.ValidationError{EventID: e.ID, Field: "ID", Reason: "duplicate ID"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if _, err := tx.Exec(insertEvent, e.ID, e.Kind, e.StartsAt.Unix(), e.EndsAt.Unix(), strings.Join(e.Resources, ","), e.Type); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveEvent removes the event with the given ID.
+func (s *Store) RemoveEvent(id int) error {
+	result, err := s.db.Exec(deleteEventID, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("event %d: %w", id, appointment.ErrEventNotFound)
+	}
+
+	return nil
+}
+
+func (s *Store) query(query string, args ...any) ([]appointment.Event, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []appointment.Event
+	for rows.Next() {
+		var e appointment.Event
+		var startsAt, endsAt int64
+		var resources string
+		if err := rows.Scan(&e.ID, &e.Kind, &startsAt, &endsAt, &resources, &e.Type); err != nil {
+			return nil, err
+		}
+
+		e.StartsAt = time.Unix(startsAt, 0).UTC()
+		e.EndsAt = time.Unix(endsAt, 0).UTC()
+		if resources != "" {
+			e.Resources = strings.Split(resources, ",")
+		}
+		events = append(events, e)
+	}
+
+	return events, errors.Join(rows.Err(), rows.Close())
+}
@@ -0,0 +1,102 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/baschtl/appointment-system/internal/memsql"
+	"github.com/baschtl/appointment-system/pkg/appointment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+
+	return t
+}
+
+// openStore returns a store on a fresh in-memory database named after the
+// test.
+func openStore(t *testing.T) *Store {
+	t.Helper()
+
+	db, err := sql.Open(memsql.DriverName, t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store, err := New(db)
+	require.NoError(t, err)
+
+	return store
+}
+
+var (
+	opening = appointment.Event{ID: 1, Kind: appointment.KindOpening, StartsAt: parseTime("2025-04-07T09:00:00Z"), EndsAt: parseTime("2025-04-07T12:00:00Z"), Resources: []string{"dr-smith", "room-1"}}
+	booking = appointment.Event{ID: 2, Kind: appointment.KindAppointment, StartsAt: parseTime("2025-04-07T09:00:00Z"), EndsAt: parseTime("2025-04-07T09:30:00Z"), Resources: []string{"dr-smith"}, Type: "follow-up"}
+	nextDay = appointment.Event{ID: 3, Kind: appointment.KindOpening, StartsAt: parseTime("2025-04-08T09:00:00Z"), EndsAt: parseTime("2025-04-08T12:00:00Z")}
+)
+
+func TestStore(t *testing.T) {
+	t.Run("should return the events it stored", func(t *testing.T) {
+		store := openStore(t)
+
+		for _, e := range []appointment.Event{nextDay, booking, opening} {
+			require.NoError(t, store.AddEvent(e))
+		}
+
+		assert.Equal(t, []appointment.Event{opening, booking, nextDay}, store.Events())
+		assert.Equal(t, []appointment.Event{opening, booking}, store.QueryEvents(parseTime("2025-04-07T00:00:00Z"), parseTime("2025-04-08T00:00:00Z")))
+		assert.NoError(t, store.Err())
+	})
+
+	t.Run("should reject invalid and duplicate events", func(t *testing.T) {
+		store := openStore(t)
+		require.NoError(t, store.AddEvent(opening))
+
+		var errs appointment.ValidationErrors
+		require.ErrorAs(t, store.AddEvent(opening), &errs)
+		assert.Equal(t, "ID", errs[0].Field)
+
+		reversed := booking
+		reversed.StartsAt, reversed.EndsAt = booking.EndsAt, booking.StartsAt
+		require.ErrorAs(t, store.AddEvent(reversed), &errs)
+
+		assert.Len(t, store.Events(), 1)
+	})
+
+	t.Run("should remove events", func(t *testing.T) {
+		store := openStore(t)
+		require.NoError(t, store.AddEvent(opening))
+
+		require.NoError(t, store.RemoveEvent(opening.ID))
+		assert.ErrorIs(t, store.RemoveEvent(opening.ID), appointment.ErrEventNotFound)
+		assert.Empty(t, store.Events())
+	})
+
+	t.Run("should book and cancel through the appointment package", func(t *testing.T) {
+		store := openStore(t)
+		require.NoError(t, store.AddEvent(opening))
+
+		require.NoError(t, appointment.Book(store, nil, booking))
+		overlapping := booking
+		overlapping.ID = appointment.NextID(store)
+		assert.ErrorIs(t, appointment.Book(store, nil, overlapping), appointment.ErrSlotUnavailable)
+
+		require.NoError(t, appointment.Cancel(store, booking.ID))
+		assert.Equal(t, []appointment.Event{opening}, store.Events())
+	})
+
+	t.Run("should remember errors of queries", func(t *testing.T) {
+		store := openStore(t)
+		require.NoError(t, store.AddEvent(opening))
+
+		memsql.SetFailing(t.Name(), true)
+		defer memsql.SetFailing(t.Name(), false)
+
+		assert.Empty(t, store.Events())
+		assert.ErrorIs(t, store.Err(), memsql.ErrFailing)
+		assert.Error(t, store.AddEvent(booking))
+	})
+}
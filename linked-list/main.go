@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/baschtl/linked-list/pkg/linkedlist"
+)
+
+func main() {
+	l := linkedlist.LinkedList[int]{}
+	fmt.Println("Content: " + l.String())
+	fmt.Println(fmt.Sprintf("Size: %d", l.Size()))
+
+	fmt.Println("Appending some nodes...")
+
+	l.Append(1)
+	l.Append(3)
+	l.Append(5)
+
+	fmt.Println("Content: " + l.String())
+	fmt.Println(fmt.Sprintf("Size: %d", l.Size()))
+
+	fmt.Println("Prepending some nodes...")
+
+	l.Prepend(-1)
+	l.Prepend(-3)
+	l.Prepend(-5)
+
+	fmt.Println("Content: " + l.String())
+	fmt.Println(fmt.Sprintf("Size: %d", l.Size()))
+
+	fmt.Println("Deleting some nodes...")
+
+	l.Delete(-5)
+	l.Delete(-1)
+	l.Delete(5)
+
+	fmt.Println("Content: " + l.String())
+	fmt.Println(fmt.Sprintf("Size: %d", l.Size()))
+
+	fmt.Println("Inserting around a node of a doubly linked list...")
+
+	d := linkedlist.DoublyLinkedList[string]{}
+	b := d.Append("b")
+	d.InsertBefore("a", b)
+	d.InsertAfter("c", b)
+
+	fmt.Println("Content: " + d.String())
+
+	d.Remove(b)
+
+	fmt.Println("Content: " + d.String())
+	fmt.Println(fmt.Sprintf("Size: %d", d.Size()))
+}
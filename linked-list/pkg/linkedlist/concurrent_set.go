@@ -0,0 +1,155 @@
+package linkedlist
+
+import (
+	"cmp"
+	"iter"
+	"sync/atomic"
+)
+
+// ConcurrentSet is a sorted set that many goroutines may change at the same
+// time without locks. It is a singly linked list in ascending order, using
+// Harris's algorithm: a node is deleted by first marking its next pointer,
+// which logically removes it and stops inserts behind it, and then
+// unlinking it, which any goroutine passing by may finish. The zero value
+// is an empty set ready to use.
+type ConcurrentSet[T cmp.Ordered] struct {
+	// head is a sentinel before the smallest value.
+	head setNode[T]
+	size atomic.Int64
+}
+
+type setNode[T cmp.Ordered] struct {
+	value T
+	next  atomic.Pointer[markedRef[T]]
+}
+
+// markedRef is an immutable pair of a successor and the deletion mark of the
+// node that points to it. Replacing the whole pair with one compare and swap
+// changes both atomically, which other languages do by stealing a pointer
+// bit.
+type markedRef[T cmp.Ordered] struct {
+	node   *setNode[T]
+	marked bool
+}
+
+// successor returns the node a reference points to. A nil reference, which
+// only the head of an empty set has, points nowhere.
+func (r *markedRef[T]) successor() *setNode[T] {
+	if r == nil {
+		return nil
+	}
+
+	return r.node
+}
+
+// find returns the last node before value, the reference it was found
+// through and the first node with a value of at least value, or nil if there
+// is none. It unlinks marked nodes on the way and starts over whenever
+// another goroutine changes the part of the list it is looking at.
+func (s *ConcurrentSet[T]) find(value T) (pred *setNode[T], predRef *markedRef[T], curr *setNode[T]) {
+retry:
+	for {
+		pred = &s.head
+		predRef = pred.next.Load()
+		curr = predRef.successor()
+
+		for curr != nil {
+			currRef := curr.next.Load()
+
+			if currRef.marked {
+				unlinked := &markedRef[T]{node: currRef.node}
+				if !pred.next.CompareAndSwap(predRef, unlinked) {
+					continue retry
+				}
+
+				predRef, curr = unlinked, currRef.node
+				continue
+			}
+
+			if curr.value >= value {
+				return pred, predRef, curr
+			}
+
+			pred, predRef, curr = curr, currRef, currRef.node
+		}
+
+		return pred, predRef, nil
+	}
+}
+
+// Insert adds the value and reports whether it was missing.
+func (s *ConcurrentSet[T]) Insert(value T) bool {
+	for {
+		pred, predRef, curr := s.find(value)
+		if curr != nil && curr.value == value {
+			return false
+		}
+
+		node := &setNode[T]{value: value}
+		node.next.Store(&markedRef[T]{node: curr})
+
+		if pred.next.CompareAndSwap(predRef, &markedRef[T]{node: node}) {
+			s.size.Add(1)
+			return true
+		}
+	}
+}
+
+// Delete removes the value and reports whether it was present.
+func (s *ConcurrentSet[T]) Delete(value T) bool {
+	for {
+		pred, predRef, curr := s.find(value)
+		if curr == nil || curr.value != value {
+			return false
+		}
+
+		succRef := curr.next.Load()
+		if succRef.marked {
+			// Another goroutine deleted it first; find will unlink it.
+			continue
+		}
+
+		// Marking is the linearization point; whoever succeeds deletes the
+		// value.
+		if !curr.next.CompareAndSwap(succRef, &markedRef[T]{node: succRef.node, marked: true}) {
+			continue
+		}
+
+		// If unlinking fails, the next find passing by does it.
+		pred.next.CompareAndSwap(predRef, &markedRef[T]{node: succRef.node})
+		s.size.Add(-1)
+
+		return true
+	}
+}
+
+// Contains reports whether the value is in the set. It never retries or
+// writes, so it finishes in a bounded number of steps.
+func (s *ConcurrentSet[T]) Contains(value T) bool {
+	curr := s.head.next.Load().successor()
+	for curr != nil && curr.value < value {
+		curr = curr.next.Load().node
+	}
+
+	return curr != nil && curr.value == value && !curr.next.Load().marked
+}
+
+// Len returns the number of values. While other goroutines change the set,
+// it may be off by the operations in progress.
+func (s *ConcurrentSet[T]) Len() int {
+	return int(s.size.Load())
+}
+
+// All returns an iterator over the values in ascending order. Values
+// inserted or deleted while iterating may or may not be seen.
+func (s *ConcurrentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for curr := s.head.next.Load().successor(); curr != nil; {
+			ref := curr.next.Load()
+			if !ref.marked && !yield(curr.value) {
+				return
+			}
+			curr = ref.node
+		}
+	}
+}
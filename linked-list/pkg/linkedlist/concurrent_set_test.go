@@ -0,0 +1,287 @@
+package linkedlist
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	t.Run("should keep values sorted and unique", func(t *testing.T) {
+		var s ConcurrentSet[int]
+
+		for _, v := range []int{5, 1, 3, 1, 4, 5} {
+			s.Insert(v)
+		}
+
+		assert.Equal(t, []int{1, 3, 4, 5}, slices.Collect(s.All()))
+		assert.Equal(t, 4, s.Len())
+	})
+
+	t.Run("should report whether values were inserted or deleted", func(t *testing.T) {
+		var s ConcurrentSet[string]
+
+		assert.True(t, s.Insert("b"))
+		assert.False(t, s.Insert("b"))
+		assert.True(t, s.Contains("b"))
+		assert.False(t, s.Contains("a"))
+
+		assert.False(t, s.Delete("a"))
+		assert.True(t, s.Delete("b"))
+		assert.False(t, s.Delete("b"))
+		assert.False(t, s.Contains("b"))
+		assert.Zero(t, s.Len())
+		assert.Empty(t, slices.Collect(s.All()))
+	})
+
+	t.Run("should handle the ends of the list", func(t *testing.T) {
+		var s ConcurrentSet[int]
+		for _, v := range []int{2, 3, 4} {
+			s.Insert(v)
+		}
+
+		s.Delete(2)
+		s.Delete(4)
+		s.Insert(1)
+		s.Insert(5)
+
+		assert.Equal(t, []int{1, 3, 5}, slices.Collect(s.All()))
+	})
+
+	t.Run("should stay consistent under concurrent inserts and deletes", func(t *testing.T) {
+		var s ConcurrentSet[int]
+		var inserted, deleted [64]atomic.Int64
+
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				r := rand.New(rand.NewPCG(uint64(g), 0))
+				for range 2000 {
+					v := r.IntN(len(inserted))
+					switch r.IntN(3) {
+					case 0:
+						if s.Insert(v) {
+							inserted[v].Add(1)
+						}
+					case 1:
+						if s.Delete(v) {
+							deleted[v].Add(1)
+						}
+					case 2:
+						s.Contains(v)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		values := slices.Collect(s.All())
+		assert.True(t, slices.IsSorted(values))
+		assert.Len(t, slices.Compact(slices.Clone(values)), len(values))
+		assert.Equal(t, len(values), s.Len())
+
+		for v := range inserted {
+			// Every successful insert of a value is followed by a successful
+			// delete, except maybe the last one.
+			present := inserted[v].Load() - deleted[v].Load()
+			assert.Equal(t, present == 1, s.Contains(v), v)
+			assert.Contains(t, []int64{0, 1}, present, v)
+		}
+	})
+
+	t.Run("should be linearizable", func(t *testing.T) {
+		for round := range 20 {
+			history := recordSetHistory(round, 4, 40, 4)
+
+			for key, ops := range history.byValue() {
+				assert.True(t, linearizable(ops), "round %d, value %d: %v", round, key, ops)
+			}
+		}
+	})
+}
+
+type setOp int
+
+const (
+	opInsert setOp = iota
+	opDelete
+	opContains
+)
+
+func (o setOp) String() string {
+	return [...]string{"Insert", "Delete", "Contains"}[o]
+}
+
+// operation is one call of a ConcurrentSet method as seen by its caller. Its
+// call and ret times come from one counter shared by all goroutines, so if a
+// returned before b was called, a.ret < b.call.
+type operation struct {
+	op        setOp
+	value     int
+	result    bool
+	call, ret int64
+}
+
+func (o operation) String() string {
+	return fmt.Sprintf("%s(%d)=%t@[%d,%d]", o.op, o.value, o.result, o.call, o.ret)
+}
+
+type history []operation
+
+// byValue splits the history by value. Operations on different values of a
+// set never affect each other, so the history is linearizable if each part
+// is, and the parts are much faster to check.
+func (h history) byValue() map[int][]operation {
+	parts := make(map[int][]operation)
+	for _, o := range h {
+		parts[o.value] = append(parts[o.value], o)
+	}
+
+	return parts
+}
+
+// recordSetHistory runs random operations on a new set from several
+// goroutines and records when each was called and returned.
+func recordSetHistory(seed, goroutines, opsPerGoroutine, values int) history {
+	var s ConcurrentSet[int]
+	var clock atomic.Int64
+
+	histories := make([]history, goroutines)
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			r := rand.New(rand.NewPCG(uint64(seed), uint64(g)))
+			for range opsPerGoroutine {
+				o := operation{op: setOp(r.IntN(3)), value: r.IntN(values)}
+
+				o.call = clock.Add(1)
+				switch o.op {
+				case opInsert:
+					o.result = s.Insert(o.value)
+				case opDelete:
+					o.result = s.Delete(o.value)
+				case opContains:
+					o.result = s.Contains(o.value)
+				}
+				o.ret = clock.Add(1)
+
+				histories[g] = append(histories[g], o)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return slices.Concat(histories...)
+}
+
+// linearizable reports whether the operations on a single value can be put
+// in an order that respects their real-time order and in which each result
+// is the one a sequential set would give. It searches the orders
+// depth-first, remembering the combinations of done operations and set
+// state that were already found to lead nowhere (Wing and Gong, with Lowe's
+// memoization).
+func linearizable(ops []operation) bool {
+	done := make([]bool, len(ops))
+	failed := make(map[string]bool)
+
+	var search func(remaining int, present bool) bool
+	search = func(remaining int, present bool) bool {
+		if remaining == 0 {
+			return true
+		}
+
+		key := fmt.Sprint(done, present)
+		if failed[key] {
+			return false
+		}
+
+		// Only operations called before every pending one returned may be
+		// linearized next.
+		minRet := int64(-1)
+		for i, o := range ops {
+			if !done[i] && (minRet < 0 || o.ret < minRet) {
+				minRet = o.ret
+			}
+		}
+
+		for i, o := range ops {
+			if done[i] || o.call > minRet {
+				continue
+			}
+
+			next, ok := applySetOp(o, present)
+			if !ok {
+				continue
+			}
+
+			done[i] = true
+			if search(remaining-1, next) {
+				return true
+			}
+			done[i] = false
+		}
+
+		failed[key] = true
+
+		return false
+	}
+
+	return search(len(ops), false)
+}
+
+// applySetOp applies the operation to a set that holds its value if present
+// is true. It returns whether the set holds the value afterwards and whether
+// the operation's result is the one a sequential set would give.
+func applySetOp(o operation, present bool) (bool, bool) {
+	switch o.op {
+	case opInsert:
+		return true, o.result == !present
+	case opDelete:
+		return false, o.result == present
+	default:
+		return present, o.result == present
+	}
+}
+
+func TestLinearizable(t *testing.T) {
+	t.Run("should accept overlapping operations in either order", func(t *testing.T) {
+		ops := []operation{
+			{op: opInsert, value: 1, result: true, call: 1, ret: 4},
+			{op: opContains, value: 1, result: false, call: 2, ret: 3},
+			{op: opDelete, value: 1, result: true, call: 5, ret: 6},
+		}
+
+		assert.True(t, linearizable(ops))
+	})
+
+	t.Run("should reject results that contradict the real-time order", func(t *testing.T) {
+		ops := []operation{
+			{op: opInsert, value: 1, result: true, call: 1, ret: 2},
+			{op: opContains, value: 1, result: false, call: 3, ret: 4},
+		}
+
+		assert.False(t, linearizable(ops))
+	})
+
+	t.Run("should reject two successful deletes of one insert", func(t *testing.T) {
+		ops := []operation{
+			{op: opInsert, value: 1, result: true, call: 1, ret: 2},
+			{op: opDelete, value: 1, result: true, call: 3, ret: 6},
+			{op: opDelete, value: 1, result: true, call: 4, ret: 5},
+		}
+
+		assert.False(t, linearizable(ops))
+	})
+}
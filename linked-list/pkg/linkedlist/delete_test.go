@@ -0,0 +1,170 @@
+package linkedlist
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkList verifies that the values, size and tail of the list agree.
+func checkList(t *testing.T, l *LinkedList[int], want []int) {
+	t.Helper()
+
+	assert.Equal(t, nilIfEmpty(want), nilIfEmpty(l.Collect()))
+	assert.Equal(t, len(want), l.Size())
+	if len(want) == 0 {
+		assert.Nil(t, l.Head())
+		assert.Nil(t, l.Tail())
+	} else {
+		require.NotNil(t, l.Tail())
+		assert.Equal(t, want[len(want)-1], l.Tail().Value)
+		assert.Nil(t, l.Tail().Next())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		delete  int
+		deleted bool
+		want    []int
+	}{
+		{"should delete the head", []int{1, 2, 3}, 1, true, []int{2, 3}},
+		{"should delete a middle node", []int{1, 2, 3}, 2, true, []int{1, 3}},
+		{"should delete the tail", []int{1, 2, 3}, 3, true, []int{1, 2}},
+		{"should delete only the first match", []int{1, 2, 1}, 1, true, []int{2, 1}},
+		{"should delete the only node", []int{1}, 1, true, nil},
+		{"should report a missing value", []int{1, 2}, 3, false, []int{1, 2}},
+		{"should report an empty list", nil, 1, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FromSeq(slices.Values(tt.values))
+			d := DoublyFromSeq(slices.Values(tt.values))
+
+			assert.Equal(t, tt.deleted, l.Delete(tt.delete))
+			assert.Equal(t, tt.deleted, d.Delete(tt.delete))
+
+			checkList(t, l, tt.want)
+			assert.Equal(t, nilIfEmpty(tt.want), nilIfEmpty(doublyValues(t, d)))
+		})
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []int
+		delete  int
+		deleted int
+		want    []int
+	}{
+		{"should delete all matches", []int{1, 2, 1, 3, 1}, 1, 3, []int{2, 3}},
+		{"should delete adjacent matches", []int{2, 1, 1, 1, 3}, 1, 3, []int{2, 3}},
+		{"should delete trailing matches", []int{2, 3, 1, 1}, 1, 2, []int{2, 3}},
+		{"should delete every node", []int{1, 1, 1}, 1, 3, nil},
+		{"should delete nothing without matches", []int{2, 3}, 1, 0, []int{2, 3}},
+		{"should delete nothing from an empty list", nil, 1, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FromSeq(slices.Values(tt.values))
+
+			assert.Equal(t, tt.deleted, l.DeleteAll(tt.delete))
+			checkList(t, l, tt.want)
+
+			l.Append(9)
+			checkList(t, l, append(slices.Clone(tt.want), 9))
+		})
+	}
+}
+
+func TestDeleteFunc(t *testing.T) {
+	even := func(v int) bool { return v%2 == 0 }
+
+	tests := []struct {
+		name    string
+		values  []int
+		pred    func(int) bool
+		deleted int
+		want    []int
+	}{
+		{"should delete matching values", []int{1, 2, 3, 4, 6}, even, 3, []int{1, 3}},
+		{"should delete every node", []int{2, 4}, even, 2, nil},
+		{"should keep non-matching values", []int{1, 3}, even, 0, []int{1, 3}},
+		{"should delete everything with an always true predicate", []int{1, 2, 3}, func(int) bool { return true }, 3, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FromSeq(slices.Values(tt.values))
+
+			assert.Equal(t, tt.deleted, l.DeleteFunc(tt.pred))
+			checkList(t, l, tt.want)
+		})
+	}
+}
+
+func TestDeleteAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		index  int
+		value  int
+		err    error
+		want   []int
+	}{
+		{"should delete the head", []int{1, 2, 3}, 0, 1, nil, []int{2, 3}},
+		{"should delete a middle node", []int{1, 2, 3}, 1, 2, nil, []int{1, 3}},
+		{"should delete the tail", []int{1, 2, 3}, 2, 3, nil, []int{1, 2}},
+		{"should delete the only node", []int{1}, 0, 1, nil, nil},
+		{"should reject a negative index", []int{1, 2}, -1, 0, ErrIndexOutOfRange, []int{1, 2}},
+		{"should reject an index past the tail", []int{1, 2}, 2, 0, ErrIndexOutOfRange, []int{1, 2}},
+		{"should reject any index of an empty list", nil, 0, 0, ErrIndexOutOfRange, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FromSeq(slices.Values(tt.values))
+
+			value, err := l.DeleteAt(tt.index)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.value, value)
+			checkList(t, l, tt.want)
+		})
+	}
+}
+
+func TestInsertAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		index  int
+		err    error
+		want   []int
+	}{
+		{"should insert at the head", []int{1, 2}, 0, nil, []int{0, 1, 2}},
+		{"should insert in the middle", []int{1, 2}, 1, nil, []int{1, 0, 2}},
+		{"should insert at the tail", []int{1, 2}, 2, nil, []int{1, 2, 0}},
+		{"should insert into an empty list", nil, 0, nil, []int{0}},
+		{"should reject a negative index", []int{1, 2}, -1, ErrIndexOutOfRange, []int{1, 2}},
+		{"should reject an index past the tail", []int{1, 2}, 3, ErrIndexOutOfRange, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FromSeq(slices.Values(tt.values))
+
+			err := l.InsertAt(tt.index, 0)
+
+			assert.ErrorIs(t, err, tt.err)
+			checkList(t, l, tt.want)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package linkedlist
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DoublyNode is an element of a DoublyLinkedList.
+type DoublyNode[T comparable] struct {
+	Value T
+	prev  *DoublyNode[T]
+	next  *DoublyNode[T]
+	// list is the list the node belongs to, or nil once it was removed.
+	list *DoublyLinkedList[T]
+}
+
+// Next returns the following node or nil if n is the last one.
+func (n *DoublyNode[T]) Next() *DoublyNode[T] {
+	return n.next
+}
+
+// Prev returns the preceding node or nil if n is the first one.
+func (n *DoublyNode[T]) Prev() *DoublyNode[T] {
+	return n.prev
+}
+
+// DoublyLinkedList is a doubly linked list. Besides adding at both ends, it
+// inserts and removes around a known node in constant time. The zero value
+// is an empty list ready to use.
+type DoublyLinkedList[T comparable] struct {
+	head *DoublyNode[T]
+	tail *DoublyNode[T]
+	size int
+}
+
+// Head returns the first node or nil if the list is empty.
+func (l *DoublyLinkedList[T]) Head() *DoublyNode[T] {
+	return l.head
+}
+
+// Tail returns the last node or nil if the list is empty.
+func (l *DoublyLinkedList[T]) Tail() *DoublyNode[T] {
+	return l.tail
+}
+
+// Append adds the value at the end of the list and returns its node.
+func (l *DoublyLinkedList[T]) Append(value T) *DoublyNode[T] {
+	return l.insert(value, l.tail, nil)
+}
+
+// Prepend adds the value at the start of the list and returns its node.
+func (l *DoublyLinkedList[T]) Prepend(value T) *DoublyNode[T] {
+	return l.insert(value, nil, l.head)
+}
+
+// InsertBefore adds the value in front of mark and returns its node. It
+// returns nil and leaves the list unchanged if mark is not in the list.
+func (l *DoublyLinkedList[T]) InsertBefore(value T, mark *DoublyNode[T]) *DoublyNode[T] {
+	if mark == nil || mark.list != l {
+		return nil
+	}
+
+	return l.insert(value, mark.prev, mark)
+}
+
+// InsertAfter adds the value behind mark and returns its node. It returns
+// nil and leaves the list unchanged if mark is not in the list.
+func (l *DoublyLinkedList[T]) InsertAfter(value T, mark *DoublyNode[T]) *DoublyNode[T] {
+	if mark == nil || mark.list != l {
+		return nil
+	}
+
+	return l.insert(value, mark, mark.next)
+}
+
+// insert links a new node between prev and next, either of which is nil at
+// the ends of the list.
+func (l *DoublyLinkedList[T]) insert(value T, prev, next *DoublyNode[T]) *DoublyNode[T] {
+	node := &DoublyNode[T]{Value: value, prev: prev, next: next, list: l}
+
+	if prev == nil {
+		l.head = node
+	} else {
+		prev.next = node
+	}
+
+	if next == nil {
+		l.tail = node
+	} else {
+		next.prev = node
+	}
+
+	l.size++
+
+	return node
+}
+
+// Remove unlinks the node and returns its value. It does nothing if the
+// node is not in the list. The node must not be nil.
+func (l *DoublyLinkedList[T]) Remove(node *DoublyNode[T]) T {
+	if node.list == l {
+		l.unlink(node)
+	}
+
+	return node.Value
+}
+
+func (l *DoublyLinkedList[T]) unlink(node *DoublyNode[T]) {
+	if node.prev == nil {
+		l.head = node.next
+	} else {
+		node.prev.next = node.next
+	}
+
+	if node.next == nil {
+		l.tail = node.prev
+	} else {
+		node.next.prev = node.prev
+	}
+
+	node.prev, node.next, node.list = nil, nil, nil
+	l.size--
+}
+
+// Delete removes the first node holding the value and reports whether there
+// was one.
+func (l *DoublyLinkedList[T]) Delete(value T) bool {
+	for current := l.head; current != nil; current = current.next {
+		if current.Value == value {
+			l.unlink(current)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Size returns the number of nodes.
+func (l *DoublyLinkedList[T]) Size() int {
+	return l.size
+}
+
+// String lists the values separated by spaces.
+func (l *DoublyLinkedList[T]) String() string {
+	b := strings.Builder{}
+
+	for current := l.head; current != nil; current = current.next {
+		fmt.Fprintf(&b, "%v ", current.Value)
+	}
+
+	return b.String()
+}
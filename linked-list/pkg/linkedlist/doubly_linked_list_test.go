@@ -0,0 +1,107 @@
+package linkedlist
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// doublyValues walks the list in both directions and checks that both
+// walks and the size agree.
+func doublyValues[T comparable](t *testing.T, l *DoublyLinkedList[T]) []T {
+	t.Helper()
+
+	var forward, backward []T
+	for n := l.Head(); n != nil; n = n.Next() {
+		forward = append(forward, n.Value)
+	}
+	for n := l.Tail(); n != nil; n = n.Prev() {
+		backward = append(backward, n.Value)
+	}
+	slices.Reverse(backward)
+
+	require.Equal(t, forward, backward)
+	require.Len(t, forward, l.Size())
+
+	return forward
+}
+
+func TestDoublyLinkedList(t *testing.T) {
+	t.Run("should append and prepend values", func(t *testing.T) {
+		l := DoublyLinkedList[int]{}
+		l.Append(1)
+		l.Append(3)
+		l.Prepend(-1)
+
+		assert.Equal(t, []int{-1, 1, 3}, doublyValues(t, &l))
+		assert.Equal(t, "-1 1 3 ", l.String())
+	})
+
+	t.Run("should insert around a node", func(t *testing.T) {
+		l := DoublyLinkedList[string]{}
+		b := l.Append("b")
+
+		l.InsertBefore("a", b)
+		l.InsertAfter("c", b)
+		assert.Equal(t, []string{"a", "b", "c"}, doublyValues(t, &l))
+
+		l.InsertBefore("front", l.Head())
+		l.InsertAfter("back", l.Tail())
+		assert.Equal(t, []string{"front", "a", "b", "c", "back"}, doublyValues(t, &l))
+	})
+
+	t.Run("should remove nodes", func(t *testing.T) {
+		l := DoublyLinkedList[int]{}
+		first := l.Append(1)
+		middle := l.Append(2)
+		last := l.Append(3)
+
+		assert.Equal(t, 2, l.Remove(middle))
+		assert.Equal(t, []int{1, 3}, doublyValues(t, &l))
+
+		l.Remove(first)
+		l.Remove(last)
+		assert.Empty(t, doublyValues(t, &l))
+		assert.Nil(t, l.Head())
+		assert.Nil(t, l.Tail())
+	})
+
+	t.Run("should ignore nodes of other lists", func(t *testing.T) {
+		l := DoublyLinkedList[int]{}
+		other := DoublyLinkedList[int]{}
+		l.Append(1)
+		foreign := other.Append(2)
+
+		l.Remove(foreign)
+		assert.Nil(t, l.InsertBefore(3, foreign))
+		assert.Nil(t, l.InsertAfter(3, foreign))
+
+		assert.Equal(t, []int{1}, doublyValues(t, &l))
+		assert.Equal(t, []int{2}, doublyValues(t, &other))
+	})
+
+	t.Run("should ignore nodes that were already removed", func(t *testing.T) {
+		l := DoublyLinkedList[int]{}
+		node := l.Append(1)
+		l.Append(2)
+
+		l.Remove(node)
+		l.Remove(node)
+
+		assert.Equal(t, []int{2}, doublyValues(t, &l))
+	})
+
+	t.Run("should delete the first matching value", func(t *testing.T) {
+		l := DoublyLinkedList[int]{}
+		for _, v := range []int{1, 2, 3, 2} {
+			l.Append(v)
+		}
+
+		l.Delete(2)
+		l.Delete(42)
+
+		assert.Equal(t, []int{1, 3, 2}, doublyValues(t, &l))
+	})
+}
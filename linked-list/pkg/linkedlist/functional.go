@@ -0,0 +1,35 @@
+package linkedlist
+
+// Map returns a new list holding the results of f for each value of l, in
+// order.
+func Map[T, U comparable](l *LinkedList[T], f func(T) U) *LinkedList[U] {
+	mapped := &LinkedList[U]{}
+	for v := range l.All() {
+		mapped.Append(f(v))
+	}
+
+	return mapped
+}
+
+// Filter returns a new list holding the values of l that satisfy pred, in
+// order.
+func Filter[T comparable](l *LinkedList[T], pred func(T) bool) *LinkedList[T] {
+	filtered := &LinkedList[T]{}
+	for v := range l.All() {
+		if pred(v) {
+			filtered.Append(v)
+		}
+	}
+
+	return filtered
+}
+
+// Reduce combines the values of l from head to tail, starting with initial.
+func Reduce[T comparable, A any](l *LinkedList[T], initial A, f func(acc A, value T) A) A {
+	acc := initial
+	for v := range l.All() {
+		acc = f(acc, v)
+	}
+
+	return acc
+}
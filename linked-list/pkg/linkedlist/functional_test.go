@@ -0,0 +1,38 @@
+package linkedlist
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctional(t *testing.T) {
+	l := FromSeq(slices.Values([]int{1, 2, 3, 4}))
+
+	t.Run("should map values into a new list", func(t *testing.T) {
+		mapped := Map(l, strconv.Itoa)
+
+		assert.Equal(t, []string{"1", "2", "3", "4"}, mapped.Collect())
+		assert.Equal(t, "4", mapped.Tail().Value)
+		assert.Equal(t, []int{1, 2, 3, 4}, l.Collect())
+	})
+
+	t.Run("should filter values into a new list", func(t *testing.T) {
+		even := Filter(l, func(v int) bool { return v%2 == 0 })
+
+		assert.Equal(t, []int{2, 4}, even.Collect())
+		assert.Equal(t, 2, even.Size())
+		assert.Equal(t, 4, l.Size())
+	})
+
+	t.Run("should reduce values from head to tail", func(t *testing.T) {
+		sum := Reduce(l, 0, func(acc, v int) int { return acc + v })
+		digits := Reduce(l, "", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+
+		assert.Equal(t, 10, sum)
+		assert.Equal(t, "1234", digits)
+		assert.Equal(t, 42, Reduce(&LinkedList[int]{}, 42, func(acc, v int) int { return acc + v }))
+	})
+}
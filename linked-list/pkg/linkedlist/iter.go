@@ -0,0 +1,123 @@
+package linkedlist
+
+import "iter"
+
+// All returns an iterator over the values from head to tail. The current
+// node may be deleted while iterating.
+func (l *LinkedList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := l.head; current != nil; {
+			next := current.next
+			if !yield(current.Value) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// Enumerate returns an iterator over the indexes and values from head to
+// tail.
+func (l *LinkedList[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range l.All() {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// AppendSeq appends the values of seq.
+func (l *LinkedList[T]) AppendSeq(seq iter.Seq[T]) {
+	for v := range seq {
+		l.Append(v)
+	}
+}
+
+// Collect returns the values as a slice.
+func (l *LinkedList[T]) Collect() []T {
+	values := make([]T, 0, l.size)
+	for v := range l.All() {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// FromSeq returns a list of the values of seq, in order.
+func FromSeq[T comparable](seq iter.Seq[T]) *LinkedList[T] {
+	l := &LinkedList[T]{}
+	l.AppendSeq(seq)
+
+	return l
+}
+
+// All returns an iterator over the values from head to tail. The current
+// node may be removed while iterating.
+func (l *DoublyLinkedList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := l.head; current != nil; {
+			next := current.next
+			if !yield(current.Value) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// Backward returns an iterator over the values from tail to head. The
+// current node may be removed while iterating.
+func (l *DoublyLinkedList[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := l.tail; current != nil; {
+			prev := current.prev
+			if !yield(current.Value) {
+				return
+			}
+			current = prev
+		}
+	}
+}
+
+// Enumerate returns an iterator over the indexes and values from head to
+// tail.
+func (l *DoublyLinkedList[T]) Enumerate() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range l.All() {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// AppendSeq appends the values of seq.
+func (l *DoublyLinkedList[T]) AppendSeq(seq iter.Seq[T]) {
+	for v := range seq {
+		l.Append(v)
+	}
+}
+
+// Collect returns the values as a slice.
+func (l *DoublyLinkedList[T]) Collect() []T {
+	values := make([]T, 0, l.size)
+	for v := range l.All() {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// DoublyFromSeq returns a doubly linked list of the values of seq, in order.
+func DoublyFromSeq[T comparable](seq iter.Seq[T]) *DoublyLinkedList[T] {
+	l := &DoublyLinkedList[T]{}
+	l.AppendSeq(seq)
+
+	return l
+}
@@ -0,0 +1,88 @@
+package linkedlist
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterators(t *testing.T) {
+	t.Run("should iterate over a list in order", func(t *testing.T) {
+		l := FromSeq(slices.Values([]int{1, 2, 3}))
+
+		assert.Equal(t, []int{1, 2, 3}, slices.Collect(l.All()))
+		assert.Equal(t, []int{1, 2, 3}, l.Collect())
+		assert.Equal(t, 3, l.Tail().Value)
+		assert.Equal(t, 3, l.Size())
+	})
+
+	t.Run("should iterate over a doubly linked list in both directions", func(t *testing.T) {
+		l := DoublyFromSeq(slices.Values([]string{"a", "b", "c"}))
+
+		assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(l.All()))
+		assert.Equal(t, []string{"c", "b", "a"}, slices.Collect(l.Backward()))
+		assert.Equal(t, []string{"a", "b", "c"}, l.Collect())
+	})
+
+	t.Run("should enumerate values with their indexes", func(t *testing.T) {
+		l := FromSeq(slices.Values([]string{"a", "b"}))
+		d := DoublyFromSeq(slices.Values([]string{"a", "b"}))
+
+		assert.Equal(t, map[int]string{0: "a", 1: "b"}, maps.Collect(l.Enumerate()))
+		assert.Equal(t, map[int]string{0: "a", 1: "b"}, maps.Collect(d.Enumerate()))
+	})
+
+	t.Run("should stop when the loop breaks", func(t *testing.T) {
+		l := FromSeq(slices.Values([]int{1, 2, 3}))
+		d := DoublyFromSeq(slices.Values([]int{1, 2, 3}))
+
+		var seen []int
+		for v := range l.All() {
+			seen = append(seen, v)
+			break
+		}
+		for v := range d.Backward() {
+			seen = append(seen, v)
+			break
+		}
+		for i := range d.Enumerate() {
+			seen = append(seen, i)
+			break
+		}
+
+		assert.Equal(t, []int{1, 3, 0}, seen)
+	})
+
+	t.Run("should allow deleting the current value while iterating", func(t *testing.T) {
+		l := FromSeq(slices.Values([]int{1, 2, 3, 4}))
+		for v := range l.All() {
+			if v%2 == 0 {
+				l.Delete(v)
+			}
+		}
+
+		d := DoublyFromSeq(slices.Values([]int{1, 2, 3, 4}))
+		for v := range d.Backward() {
+			if v%2 == 1 {
+				d.Delete(v)
+			}
+		}
+
+		assert.Equal(t, []int{1, 3}, l.Collect())
+		assert.Equal(t, []int{2, 4}, d.Collect())
+	})
+
+	t.Run("should build lists from slices and maps helpers", func(t *testing.T) {
+		keys := FromSeq(slices.Values(slices.Sorted(maps.Keys(map[string]int{"b": 2, "a": 1}))))
+
+		l := LinkedList[int]{}
+		l.Append(0)
+		l.AppendSeq(slices.Values([]int{1, 2}))
+
+		assert.Equal(t, []string{"a", "b"}, keys.Collect())
+		assert.Equal(t, []int{0, 1, 2}, l.Collect())
+		assert.Empty(t, FromSeq(slices.Values([]int(nil))).Collect())
+	})
+}
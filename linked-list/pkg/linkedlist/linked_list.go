@@ -0,0 +1,301 @@
+// Package linkedlist provides generic singly and doubly linked lists.
+package linkedlist
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrIndexOutOfRange = errors.New("index out of range")
+
+// Node is an element of a LinkedList.
+type Node[T comparable] struct {
+	Value T
+	next  *Node[T]
+}
+
+// Next returns the following node or nil if n is the last one.
+func (n *Node[T]) Next() *Node[T] {
+	return n.next
+}
+
+// LinkedList is a singly linked list. It keeps a pointer to its last node
+// and its length, so that Append and Size take constant time. The zero value
+// is an empty list ready to use.
+type LinkedList[T comparable] struct {
+	head *Node[T]
+	tail *Node[T]
+	size int
+}
+
+// Head returns the first node or nil if the list is empty.
+func (l *LinkedList[T]) Head() *Node[T] {
+	return l.head
+}
+
+// Tail returns the last node or nil if the list is empty.
+func (l *LinkedList[T]) Tail() *Node[T] {
+	return l.tail
+}
+
+// Append adds the value at the end of the list.
+func (l *LinkedList[T]) Append(value T) {
+	node := &Node[T]{Value: value}
+
+	if l.head == nil {
+		l.head = node
+	} else {
+		l.tail.next = node
+	}
+
+	l.tail = node
+	l.size++
+}
+
+// Prepend adds the value at the start of the list.
+func (l *LinkedList[T]) Prepend(value T) {
+	node := &Node[T]{Value: value, next: l.head}
+
+	if l.head == nil {
+		l.tail = node
+	}
+
+	l.head = node
+	l.size++
+}
+
+// Delete removes the first node holding the value and reports whether there
+// was one.
+func (l *LinkedList[T]) Delete(value T) bool {
+	var previous *Node[T]
+	current := l.head
+
+	for current != nil {
+		if current.Value == value {
+			l.unlink(previous, current)
+			return true
+		}
+		previous = current
+		current = current.next
+	}
+
+	return false
+}
+
+// DeleteAll removes all nodes holding the value and returns how many it
+// removed.
+func (l *LinkedList[T]) DeleteAll(value T) int {
+	return l.DeleteFunc(func(v T) bool { return v == value })
+}
+
+// DeleteFunc removes all nodes whose value satisfies pred and returns how
+// many it removed.
+func (l *LinkedList[T]) DeleteFunc(pred func(T) bool) int {
+	deleted := 0
+
+	var previous *Node[T]
+	current := l.head
+
+	for current != nil {
+		next := current.next
+		if pred(current.Value) {
+			l.unlink(previous, current)
+			deleted++
+		} else {
+			previous = current
+		}
+		current = next
+	}
+
+	return deleted
+}
+
+// DeleteAt removes the node at the index and returns its value. It returns
+// an error wrapping ErrIndexOutOfRange unless 0 <= index < Size().
+func (l *LinkedList[T]) DeleteAt(index int) (T, error) {
+	if index < 0 || index >= l.size {
+		var zero T
+		return zero, fmt.Errorf("delete at %d of %d: %w", index, l.size, ErrIndexOutOfRange)
+	}
+
+	previous := l.nodeBefore(index)
+	node := l.head
+	if previous != nil {
+		node = previous.next
+	}
+
+	l.unlink(previous, node)
+
+	return node.Value, nil
+}
+
+// InsertAt inserts the value so that it ends up at the index. It returns an
+// error wrapping ErrIndexOutOfRange unless 0 <= index <= Size().
+func (l *LinkedList[T]) InsertAt(index int, value T) error {
+	if index < 0 || index > l.size {
+		return fmt.Errorf("insert at %d of %d: %w", index, l.size, ErrIndexOutOfRange)
+	}
+
+	switch index {
+	case 0:
+		l.Prepend(value)
+	case l.size:
+		l.Append(value)
+	default:
+		previous := l.nodeBefore(index)
+		previous.next = &Node[T]{Value: value, next: previous.next}
+		l.size++
+	}
+
+	return nil
+}
+
+// nodeBefore returns the node preceding the index, or nil for index 0.
+func (l *LinkedList[T]) nodeBefore(index int) *Node[T] {
+	if index == 0 {
+		return nil
+	}
+
+	previous := l.head
+	for range index - 1 {
+		previous = previous.next
+	}
+
+	return previous
+}
+
+// unlink removes node, which follows previous, or is the head if previous is
+// nil.
+func (l *LinkedList[T]) unlink(previous, node *Node[T]) {
+	if previous == nil {
+		l.head = node.next
+	} else {
+		previous.next = node.next
+	}
+
+	if l.tail == node {
+		l.tail = previous
+	}
+
+	node.next = nil
+	l.size--
+}
+
+// Find returns the first node whose value satisfies pred, or nil if there is
+// none.
+func (l *LinkedList[T]) Find(pred func(T) bool) *Node[T] {
+	for current := l.head; current != nil; current = current.next {
+		if pred(current.Value) {
+			return current
+		}
+	}
+
+	return nil
+}
+
+// IndexOf returns the index of the first node holding the value, or -1 if
+// there is none.
+func (l *LinkedList[T]) IndexOf(value T) int {
+	for i, v := range l.Enumerate() {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Contains reports whether a node holds the value.
+func (l *LinkedList[T]) Contains(value T) bool {
+	return l.IndexOf(value) >= 0
+}
+
+// Reverse reverses the order of the nodes in place.
+func (l *LinkedList[T]) Reverse() {
+	var previous *Node[T]
+	current := l.head
+	l.tail = current
+
+	for current != nil {
+		next := current.next
+		current.next = previous
+		previous = current
+		current = next
+	}
+
+	l.head = previous
+}
+
+// Sort sorts the nodes in place by cmp, which returns a negative number if
+// a sorts before b, a positive number if it sorts after b and zero if their
+// order does not matter. Equal values keep their order. It relinks the
+// nodes with a merge sort, taking O(n log n) time and no extra memory
+// besides O(log n) stack.
+func (l *LinkedList[T]) Sort(cmp func(a, b T) int) {
+	l.head = mergeSort(l.head, l.size, cmp)
+
+	l.tail = l.head
+	for l.tail != nil && l.tail.next != nil {
+		l.tail = l.tail.next
+	}
+}
+
+// mergeSort sorts the n nodes starting at head and returns the new head.
+// The last sorted node's next is nil.
+func mergeSort[T comparable](head *Node[T], n int, cmp func(a, b T) int) *Node[T] {
+	if n <= 1 {
+		if head != nil {
+			head.next = nil
+		}
+		return head
+	}
+
+	middle := head
+	for range n/2 - 1 {
+		middle = middle.next
+	}
+	right := middle.next
+
+	return merge(mergeSort(head, n/2, cmp), mergeSort(right, n-n/2, cmp), cmp)
+}
+
+// merge merges two sorted lists, taking from the left one on ties so that
+// the sort is stable.
+func merge[T comparable](left, right *Node[T], cmp func(a, b T) int) *Node[T] {
+	var head Node[T]
+	last := &head
+
+	for left != nil && right != nil {
+		if cmp(left.Value, right.Value) <= 0 {
+			last.next, left = left, left.next
+		} else {
+			last.next, right = right, right.next
+		}
+		last = last.next
+	}
+
+	if left != nil {
+		last.next = left
+	} else {
+		last.next = right
+	}
+
+	return head.next
+}
+
+// Size returns the number of nodes.
+func (l *LinkedList[T]) Size() int {
+	return l.size
+}
+
+// String lists the values separated by spaces.
+func (l *LinkedList[T]) String() string {
+	b := strings.Builder{}
+
+	for current := l.head; current != nil; current = current.next {
+		fmt.Fprintf(&b, "%v ", current.Value)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package linkedlist
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// values walks the list from the head.
+func values[T comparable](l *LinkedList[T]) []T {
+	var vs []T
+	for n := l.Head(); n != nil; n = n.Next() {
+		vs = append(vs, n.Value)
+	}
+
+	return vs
+}
+
+func TestLinkedList(t *testing.T) {
+	t.Run("should append and prepend values", func(t *testing.T) {
+		l := LinkedList[int]{}
+		l.Append(1)
+		l.Append(3)
+		l.Prepend(-1)
+
+		assert.Equal(t, []int{-1, 1, 3}, values(&l))
+		assert.Equal(t, 3, l.Size())
+		assert.Equal(t, 3, l.Tail().Value)
+		assert.Equal(t, "-1 1 3 ", l.String())
+	})
+
+	t.Run("should keep the tail when prepending to an empty list", func(t *testing.T) {
+		l := LinkedList[string]{}
+		l.Prepend("a")
+		l.Append("b")
+
+		assert.Equal(t, []string{"a", "b"}, values(&l))
+		assert.Equal(t, "b", l.Tail().Value)
+	})
+
+	t.Run("should delete the first matching value", func(t *testing.T) {
+		l := LinkedList[int]{}
+		for _, v := range []int{1, 2, 3, 2} {
+			l.Append(v)
+		}
+
+		l.Delete(2)
+		assert.Equal(t, []int{1, 3, 2}, values(&l))
+
+		l.Delete(42)
+		assert.Equal(t, 3, l.Size())
+	})
+
+	t.Run("should move the tail when deleting the last node", func(t *testing.T) {
+		l := LinkedList[int]{}
+		l.Append(1)
+		l.Append(2)
+
+		l.Delete(2)
+		assert.Equal(t, 1, l.Tail().Value)
+
+		l.Append(3)
+		assert.Equal(t, []int{1, 3}, values(&l))
+
+		l.Delete(1)
+		l.Delete(3)
+		assert.Nil(t, l.Head())
+		assert.Nil(t, l.Tail())
+		assert.Zero(t, l.Size())
+
+		l.Append(4)
+		assert.Equal(t, []int{4}, values(&l))
+	})
+}
+
+func TestLinkedListSearch(t *testing.T) {
+	l := FromSeq(slices.Values([]string{"apple", "banana", "cherry", "banana"}))
+
+	t.Run("should find the first matching node", func(t *testing.T) {
+		node := l.Find(func(v string) bool { return strings.HasPrefix(v, "b") })
+
+		assert.Equal(t, "banana", node.Value)
+		assert.Equal(t, "cherry", node.Next().Value)
+		assert.Nil(t, l.Find(func(v string) bool { return v == "kiwi" }))
+	})
+
+	t.Run("should return the index of the first matching value", func(t *testing.T) {
+		assert.Equal(t, 0, l.IndexOf("apple"))
+		assert.Equal(t, 1, l.IndexOf("banana"))
+		assert.Equal(t, -1, l.IndexOf("kiwi"))
+	})
+
+	t.Run("should tell whether a value is contained", func(t *testing.T) {
+		assert.True(t, l.Contains("cherry"))
+		assert.False(t, l.Contains("kiwi"))
+		assert.False(t, (&LinkedList[string]{}).Contains(""))
+	})
+}
+
+func TestLinkedListReverse(t *testing.T) {
+	for _, values := range [][]int{nil, {1}, {1, 2}, {1, 2, 3, 4, 5}} {
+		l := FromSeq(slices.Values(values))
+
+		l.Reverse()
+
+		want := slices.Clone(values)
+		slices.Reverse(want)
+		assert.Equal(t, want, nilIfEmpty(l.Collect()))
+		if len(values) > 0 {
+			assert.Equal(t, values[0], l.Tail().Value)
+		}
+
+		l.Append(6)
+		assert.Equal(t, append(want, 6), l.Collect())
+	}
+}
+
+// nilIfEmpty turns an empty slice into nil, to compare it with a nil slice.
+func nilIfEmpty[T any](s []T) []T {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return s
+}
+
+func TestLinkedListSort(t *testing.T) {
+	t.Run("should sort values", func(t *testing.T) {
+		for _, values := range [][]int{nil, {1}, {2, 1}, {5, 3, 1, 4, 2}, {1, 2, 3, 4}, {4, 3, 2, 1, 0, -1, 7}} {
+			l := FromSeq(slices.Values(values))
+
+			l.Sort(cmp.Compare[int])
+
+			want := slices.Sorted(slices.Values(values))
+			assert.Equal(t, nilIfEmpty(want), nilIfEmpty(l.Collect()))
+			assert.Equal(t, len(values), l.Size())
+			if len(values) > 0 {
+				assert.Equal(t, want[len(want)-1], l.Tail().Value)
+				assert.Nil(t, l.Tail().Next())
+			}
+		}
+	})
+
+	t.Run("should keep the order of equal values", func(t *testing.T) {
+		type person struct {
+			name string
+			age  int
+		}
+		l := FromSeq(slices.Values([]person{
+			{"ann", 30}, {"bob", 25}, {"cid", 30}, {"dan", 25}, {"eve", 20},
+		}))
+
+		l.Sort(func(a, b person) int { return cmp.Compare(a.age, b.age) })
+
+		names := Map(l, func(p person) string { return p.name })
+		assert.Equal(t, []string{"eve", "bob", "dan", "ann", "cid"}, names.Collect())
+	})
+
+	t.Run("should sort long lists", func(t *testing.T) {
+		values := rand.Perm(10_000)
+		l := FromSeq(slices.Values(values))
+
+		l.Sort(func(a, b int) int { return b - a })
+
+		assert.True(t, slices.IsSortedFunc(l.Collect(), func(a, b int) int { return b - a }))
+		assert.Equal(t, 0, l.Tail().Value)
+	})
+}
@@ -0,0 +1,273 @@
+package linkedlist
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+)
+
+const (
+	// skipListMaxLevel bounds the height of nodes. With a promotion
+	// probability of 1/4 it suits lists of up to 4^32 entries.
+	skipListMaxLevel = 32
+	skipListP        = 4
+)
+
+// skipLink points to the next node on one level. span is the number of
+// level 0 steps the link skips, which makes rank and select queries
+// logarithmic.
+type skipLink[K cmp.Ordered, V any] struct {
+	node *skipNode[K, V]
+	span int
+}
+
+type skipNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []skipLink[K, V]
+}
+
+// SkipList is a sorted map from keys to values. It is a linked list with
+// additional levels of links that skip over ever more nodes, so that
+// lookups, inserts and deletes take O(log n) expected time. It also answers
+// ordered queries: ranges, floor and ceiling, and the rank of a key or the
+// key at a rank. The zero value is an empty skip list ready to use. It is
+// not safe for concurrent use.
+type SkipList[K cmp.Ordered, V any] struct {
+	// head is a sentinel before the smallest key with a link on every level.
+	head  skipNode[K, V]
+	level int
+	size  int
+}
+
+func (s *SkipList[K, V]) init() {
+	if s.head.next == nil {
+		s.head.next = make([]skipLink[K, V], skipListMaxLevel)
+		s.level = 1
+	}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.IntN(skipListP) == 0 {
+		level++
+	}
+
+	return level
+}
+
+// before returns the last node whose key is less than key, or the head if
+// there is none, together with the number of nodes up to and including it.
+func (s *SkipList[K, V]) before(key K) (*skipNode[K, V], int) {
+	x, rank := &s.head, 0
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && x.next[i].node.key < key {
+			rank += x.next[i].span
+			x = x.next[i].node
+		}
+	}
+
+	return x, rank
+}
+
+// Len returns the number of keys.
+func (s *SkipList[K, V]) Len() int {
+	return s.size
+}
+
+// Search returns the value stored for the key.
+func (s *SkipList[K, V]) Search(key K) (V, bool) {
+	if s.size > 0 {
+		x, _ := s.before(key)
+		if n := x.next[0].node; n != nil && n.key == key {
+			return n.value, true
+		}
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Insert stores the value for the key and reports whether the key is new.
+// The value of an existing key is replaced.
+func (s *SkipList[K, V]) Insert(key K, value V) bool {
+	s.init()
+
+	var update [skipListMaxLevel]*skipNode[K, V]
+	var rank [skipListMaxLevel]int
+
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i].node != nil && x.next[i].node.key < key {
+			rank[i] += x.next[i].span
+			x = x.next[i].node
+		}
+		update[i] = x
+	}
+
+	if n := x.next[0].node; n != nil && n.key == key {
+		n.value = value
+		return false
+	}
+
+	level := randomLevel()
+	for i := s.level; i < level; i++ {
+		update[i] = &s.head
+		s.head.next[i].span = s.size
+	}
+	s.level = max(s.level, level)
+
+	node := &skipNode[K, V]{key: key, value: value, next: make([]skipLink[K, V], level)}
+	for i := range level {
+		link := &update[i].next[i]
+		// rank[0]-rank[i] nodes lie between update[i] and the new node.
+		node.next[i] = skipLink[K, V]{node: link.node, span: link.span - (rank[0] - rank[i])}
+		*link = skipLink[K, V]{node: node, span: rank[0] - rank[i] + 1}
+	}
+	for i := level; i < s.level; i++ {
+		update[i].next[i].span++
+	}
+
+	s.size++
+
+	return true
+}
+
+// Delete removes the key and reports whether it was present.
+func (s *SkipList[K, V]) Delete(key K) bool {
+	if s.size == 0 {
+		return false
+	}
+
+	var update [skipListMaxLevel]*skipNode[K, V]
+
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && x.next[i].node.key < key {
+			x = x.next[i].node
+		}
+		update[i] = x
+	}
+
+	node := x.next[0].node
+	if node == nil || node.key != key {
+		return false
+	}
+
+	for i := range s.level {
+		link := &update[i].next[i]
+		if link.node == node {
+			*link = skipLink[K, V]{node: node.next[i].node, span: link.span + node.next[i].span - 1}
+		} else {
+			link.span--
+		}
+	}
+
+	for s.level > 1 && s.head.next[s.level-1].node == nil {
+		s.level--
+	}
+	s.size--
+
+	return true
+}
+
+// Floor returns the entry with the greatest key less than or equal to key.
+func (s *SkipList[K, V]) Floor(key K) (K, V, bool) {
+	if s.size > 0 {
+		x, _ := s.before(key)
+		if n := x.next[0].node; n != nil && n.key == key {
+			return n.key, n.value, true
+		}
+		if x != &s.head {
+			return x.key, x.value, true
+		}
+	}
+
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to
+// key.
+func (s *SkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	if s.size > 0 {
+		x, _ := s.before(key)
+		if n := x.next[0].node; n != nil {
+			return n.key, n.value, true
+		}
+	}
+
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Rank returns the number of keys less than key, which is the index the key
+// has or would have in sorted order.
+func (s *SkipList[K, V]) Rank(key K) int {
+	if s.size == 0 {
+		return 0
+	}
+
+	_, rank := s.before(key)
+
+	return rank
+}
+
+// Select returns the entry at the index in sorted order, starting at 0.
+func (s *SkipList[K, V]) Select(index int) (K, V, bool) {
+	if index < 0 || index >= s.size {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+
+	x, traversed := &s.head, 0
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i].node != nil && traversed+x.next[i].span <= index+1 {
+			traversed += x.next[i].span
+			x = x.next[i].node
+		}
+		if traversed == index+1 {
+			break
+		}
+	}
+
+	return x.key, x.value, true
+}
+
+// Range returns an iterator over the entries with keys from lo to hi,
+// both inclusive, in ascending order.
+func (s *SkipList[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if s.size == 0 {
+			return
+		}
+
+		x, _ := s.before(lo)
+		for n := x.next[0].node; n != nil && n.key <= hi; n = n.next[0].node {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over all entries in ascending order of keys.
+func (s *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if s.size == 0 {
+			return
+		}
+
+		for n := s.head.next[0].node; n != nil; n = n.next[0].node {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package linkedlist
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkSkipList verifies that every level is sorted and that the span of
+// every link equals the number of level 0 steps it skips.
+func checkSkipList[V any](t *testing.T, s *SkipList[int, V]) {
+	t.Helper()
+
+	if s.head.next == nil {
+		return
+	}
+
+	position := map[*skipNode[int, V]]int{&s.head: 0}
+	i := 0
+	for n := s.head.next[0].node; n != nil; n = n.next[0].node {
+		i++
+		position[n] = i
+	}
+	require.Equal(t, s.size, i)
+
+	for level := range s.level {
+		for x := &s.head; x.next[level].node != nil; x = x.next[level].node {
+			next := x.next[level].node
+			require.Less(t, position[x], position[next], "level %d is not sorted", level)
+			require.Equal(t, position[next]-position[x], x.next[level].span, "span on level %d", level)
+		}
+	}
+}
+
+func TestSkipList(t *testing.T) {
+	t.Run("should store, replace and delete entries", func(t *testing.T) {
+		var s SkipList[string, int]
+
+		assert.True(t, s.Insert("b", 2))
+		assert.True(t, s.Insert("a", 1))
+		assert.False(t, s.Insert("b", 20))
+
+		value, found := s.Search("b")
+		assert.True(t, found)
+		assert.Equal(t, 20, value)
+
+		_, found = s.Search("c")
+		assert.False(t, found)
+
+		assert.True(t, s.Delete("a"))
+		assert.False(t, s.Delete("a"))
+		assert.Equal(t, 1, s.Len())
+	})
+
+	t.Run("should answer queries on an empty skip list", func(t *testing.T) {
+		var s SkipList[int, string]
+
+		_, found := s.Search(1)
+		assert.False(t, found)
+		_, _, found = s.Floor(1)
+		assert.False(t, found)
+		_, _, found = s.Ceiling(1)
+		assert.False(t, found)
+		_, _, found = s.Select(0)
+		assert.False(t, found)
+		assert.Zero(t, s.Rank(1))
+		assert.False(t, s.Delete(1))
+		assert.Empty(t, maps.Collect(s.Range(0, 10)))
+		assert.Empty(t, maps.Collect(s.All()))
+	})
+
+	t.Run("should iterate over ranges in order", func(t *testing.T) {
+		var s SkipList[int, string]
+		for _, k := range []int{50, 10, 40, 20, 30} {
+			s.Insert(k, "")
+		}
+
+		var keys []int
+		for k := range s.Range(15, 40) {
+			keys = append(keys, k)
+		}
+		assert.Equal(t, []int{20, 30, 40}, keys)
+
+		keys = nil
+		for k := range s.All() {
+			keys = append(keys, k)
+		}
+		assert.Equal(t, []int{10, 20, 30, 40, 50}, keys)
+
+		keys = nil
+		for k := range s.Range(0, 100) {
+			keys = append(keys, k)
+			if k == 20 {
+				break
+			}
+		}
+		assert.Equal(t, []int{10, 20}, keys)
+	})
+
+	t.Run("should find floor and ceiling entries", func(t *testing.T) {
+		var s SkipList[int, string]
+		s.Insert(10, "ten")
+		s.Insert(20, "twenty")
+
+		key, value, found := s.Floor(15)
+		assert.True(t, found)
+		assert.Equal(t, 10, key)
+		assert.Equal(t, "ten", value)
+
+		key, _, _ = s.Floor(20)
+		assert.Equal(t, 20, key)
+		_, _, found = s.Floor(5)
+		assert.False(t, found)
+
+		key, value, found = s.Ceiling(15)
+		assert.True(t, found)
+		assert.Equal(t, 20, key)
+		assert.Equal(t, "twenty", value)
+
+		key, _, _ = s.Ceiling(10)
+		assert.Equal(t, 10, key)
+		_, _, found = s.Ceiling(25)
+		assert.False(t, found)
+	})
+
+	t.Run("should rank keys and select by rank", func(t *testing.T) {
+		var s SkipList[int, string]
+		for _, k := range []int{30, 10, 20} {
+			s.Insert(k, "")
+		}
+
+		assert.Equal(t, 0, s.Rank(5))
+		assert.Equal(t, 1, s.Rank(20))
+		assert.Equal(t, 2, s.Rank(25))
+		assert.Equal(t, 3, s.Rank(35))
+
+		for i, want := range []int{10, 20, 30} {
+			key, _, found := s.Select(i)
+			assert.True(t, found)
+			assert.Equal(t, want, key)
+		}
+		_, _, found := s.Select(3)
+		assert.False(t, found)
+		_, _, found = s.Select(-1)
+		assert.False(t, found)
+	})
+
+	t.Run("should behave like a sorted slice under random operations", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		var s SkipList[int, int]
+		var model []int
+
+		for step := range 5000 {
+			key := r.IntN(500)
+			i, present := slices.BinarySearch(model, key)
+
+			if r.IntN(3) == 0 {
+				require.Equal(t, present, s.Delete(key))
+				if present {
+					model = slices.Delete(model, i, i+1)
+				}
+			} else {
+				require.Equal(t, !present, s.Insert(key, -key))
+				if !present {
+					model = slices.Insert(model, i, key)
+				}
+			}
+
+			require.Equal(t, len(model), s.Len())
+			require.Equal(t, i, s.Rank(key))
+
+			if step%100 == 0 {
+				checkSkipList(t, &s)
+
+				for rank, want := range model {
+					key, value, found := s.Select(rank)
+					require.True(t, found)
+					require.Equal(t, want, key)
+					require.Equal(t, -want, value)
+				}
+
+				lo, hi := r.IntN(500), r.IntN(500)
+				var got []int
+				for k := range s.Range(lo, hi) {
+					got = append(got, k)
+				}
+				var want []int
+				for _, k := range model {
+					if lo <= k && k <= hi {
+						want = append(want, k)
+					}
+				}
+				require.Equal(t, want, got)
+			}
+		}
+	})
+}
+
+func BenchmarkSkipList(b *testing.B) {
+	var s SkipList[int, int]
+	for i := range 100_000 {
+		s.Insert(rand.IntN(1_000_000), i)
+	}
+
+	b.Run("Search", func(b *testing.B) {
+		for b.Loop() {
+			s.Search(rand.IntN(1_000_000))
+		}
+	})
+
+	b.Run("Rank", func(b *testing.B) {
+		for b.Loop() {
+			s.Rank(rand.IntN(1_000_000))
+		}
+	})
+}
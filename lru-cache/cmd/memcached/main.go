@@ -0,0 +1,49 @@
+// Command memcached serves an LRU cache over the memcached text protocol.
+//
+// Usage:
+//
+//	memcached [-addr :11211] [-max-bytes 67108864]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/baschtl/lru-cache/pkg/memcache"
+)
+
+func main() {
+	addr := flag.String("addr", ":11211", "TCP address to listen on")
+	maxBytes := flag.Int64("max-bytes", 64<<20, "maximum number of bytes of keys and values to keep")
+	flag.Parse()
+
+	if *maxBytes < 1 {
+		log.Fatalf("-max-bytes must be positive")
+	}
+
+	server := memcache.NewServer(*maxBytes)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+
+	go func() {
+		fmt.Printf("Server starting on %s\n", *addr)
+		if err := server.ListenAndServe(*addr); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Fatalf("ListenAndServe error: %v", err)
+		}
+	}()
+
+	<-stop
+	fmt.Println("Shutting down...")
+
+	if err := server.Close(); err != nil {
+		log.Fatalf("Server shutdown with error: %v", err)
+	}
+
+	fmt.Println("Successfully shut down.")
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// arcList names the four lists of an ARCCache.
+type arcList int
+
+const (
+	arcT1 arcList = iota // resident, seen once
+	arcT2                // resident, seen at least twice
+	arcB1                // ghost keys evicted from T1
+	arcB2                // ghost keys evicted from T2
+)
+
+type arcEntry[K comparable, V any] struct {
+	key   K
+	value V
+	list  arcList
+}
+
+// ARCCache implements the Adaptive Replacement Cache policy by Megiddo and
+// Modha. It splits its capacity between recently and frequently used
+// entries and shifts the split, p, towards whichever side would have
+// produced a hit for recently evicted keys. This keeps a hot set resident
+// during scans without tuning.
+//
+// ARCCache is safe for concurrent use.
+type ARCCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	// p is the target size of T1.
+	p     int
+	lists [4]*list.List
+	items map[K]*list.Element
+}
+
+// NewARCCache panics if capacity is less than 1.
+func NewARCCache[K comparable, V any](capacity int) *ARCCache[K, V] {
+	checkCapacity(capacity)
+
+	c := &ARCCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+	}
+	for i := range c.lists {
+		c.lists[i] = list.New()
+	}
+
+	return c
+}
+
+func (c *ARCCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.items[key]
+	if !found || !c.resident(elem) {
+		var zero V
+
+		return zero, false
+	}
+
+	c.moveTo(elem, arcT2)
+
+	return elem.Value.(*arcEntry[K, V]).value, true
+}
+
+func (c *ARCCache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t1, t2, b1, b2 := c.lists[arcT1], c.lists[arcT2], c.lists[arcB1], c.lists[arcB2]
+
+	if elem, found := c.items[key]; found {
+		e := elem.Value.(*arcEntry[K, V])
+		e.value = value
+
+		switch e.list {
+		case arcB1:
+			// A miss that a larger T1 would have avoided: grow T1's target.
+			c.p = min(c.capacity, c.p+max(b2.Len()/b1.Len(), 1))
+			c.replace(false)
+		case arcB2:
+			// A miss that a larger T2 would have avoided: shrink T1's target.
+			c.p = max(0, c.p-max(b1.Len()/b2.Len(), 1))
+			c.replace(true)
+		}
+
+		c.moveTo(elem, arcT2)
+
+		return
+	}
+
+	switch {
+	case t1.Len()+b1.Len() >= c.capacity:
+		if t1.Len() < c.capacity {
+			c.drop(b1.Back())
+			c.replace(false)
+		} else if t1.Len() > 0 {
+			c.drop(t1.Back())
+		}
+	case t1.Len()+t2.Len()+b1.Len()+b2.Len() >= c.capacity:
+		if t1.Len()+t2.Len()+b1.Len()+b2.Len() >= 2*c.capacity && b2.Len() > 0 {
+			c.drop(b2.Back())
+		}
+		c.replace(false)
+	}
+
+	c.items[key] = t1.PushFront(&arcEntry[K, V]{key: key, value: value, list: arcT1})
+}
+
+func (c *ARCCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.items[key]
+	if !found {
+		return false
+	}
+
+	resident := c.resident(elem)
+	c.drop(elem)
+
+	return resident
+}
+
+func (c *ARCCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lists[arcT1].Len() + c.lists[arcT2].Len()
+}
+
+// replace makes room for one entry by moving the least recently used entry
+// of T1 or T2 to the corresponding ghost list, depending on whether T1 is
+// above its target size.
+func (c *ARCCache[K, V]) replace(inB2 bool) {
+	t1, t2 := c.lists[arcT1], c.lists[arcT2]
+	if t1.Len()+t2.Len() < c.capacity {
+		return
+	}
+
+	if t1.Len() > 0 && (t1.Len() > c.p || (inB2 && t1.Len() == c.p) || t2.Len() == 0) {
+		c.moveTo(t1.Back(), arcB1)
+	} else if t2.Len() > 0 {
+		c.moveTo(t2.Back(), arcB2)
+	}
+}
+
+// moveTo moves the element to the front of the given list. Values of
+// entries moved to a ghost list are dropped.
+func (c *ARCCache[K, V]) moveTo(elem *list.Element, to arcList) {
+	e := elem.Value.(*arcEntry[K, V])
+	c.lists[e.list].Remove(elem)
+
+	e.list = to
+	if to == arcB1 || to == arcB2 {
+		var zero V
+		e.value = zero
+	}
+	c.items[e.key] = c.lists[to].PushFront(e)
+}
+
+func (c *ARCCache[K, V]) drop(elem *list.Element) {
+	e := elem.Value.(*arcEntry[K, V])
+	c.lists[e.list].Remove(elem)
+	delete(c.items, e.key)
+}
+
+func (c *ARCCache[K, V]) resident(elem *list.Element) bool {
+	l := elem.Value.(*arcEntry[K, V]).list
+
+	return l == arcT1 || l == arcT2
+}
@@ -0,0 +1,37 @@
+// Package cachetest provides helpers for testing code that uses caches.
+package cachetest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a cache.Clock that only moves when Advance is called, so that
+// tests can control expiry.
+//
+// Clock is safe for concurrent use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock set to a fixed time.
+func NewClock() *Clock {
+	return &Clock{now: time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC)}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
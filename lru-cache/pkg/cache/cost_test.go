@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func byteSize(key string, value []byte) int64 {
+	return int64(len(value))
+}
+
+func TestCost(t *testing.T) {
+	t.Run("should evict least recently used entries until the cost fits the budget", func(t *testing.T) {
+		lru := NewLRUCacheWithCost(10, byteSize)
+		lru.Put("a", make([]byte, 4))
+		lru.Put("b", make([]byte, 4))
+		lru.Get("a")
+
+		lru.Put("c", make([]byte, 5))
+
+		_, found := lru.Get("b")
+		assert.False(t, found)
+		assert.Equal(t, int64(9), lru.Cost())
+		assert.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("should evict several entries for one large entry", func(t *testing.T) {
+		lru := NewLRUCacheWithCost(10, byteSize)
+		for _, key := range []string{"a", "b", "c", "d", "e"} {
+			lru.Put(key, make([]byte, 2))
+		}
+
+		lru.Put("big", make([]byte, 10))
+
+		assert.Equal(t, 1, lru.Len())
+		assert.Equal(t, int64(10), lru.Cost())
+	})
+
+	t.Run("should account for updated values", func(t *testing.T) {
+		lru := NewLRUCacheWithCost(10, byteSize)
+		lru.Put("a", make([]byte, 4))
+		lru.Put("b", make([]byte, 4))
+
+		lru.Put("b", make([]byte, 8))
+
+		_, found := lru.Get("a")
+		assert.False(t, found)
+		assert.Equal(t, int64(8), lru.Cost())
+	})
+
+	t.Run("should reject entries larger than the budget", func(t *testing.T) {
+		lru := NewLRUCacheWithCost(10, byteSize)
+		require.NoError(t, lru.TryPut("a", make([]byte, 4)))
+
+		err := lru.TryPut("huge", make([]byte, 11))
+
+		assert.ErrorIs(t, err, ErrTooLarge)
+		assert.Equal(t, 1, lru.Len())
+		assert.Equal(t, int64(4), lru.Cost())
+	})
+
+	t.Run("should drop entries larger than the budget on Put", func(t *testing.T) {
+		lru := NewLRUCacheWithCost(10, byteSize)
+		lru.Put("a", make([]byte, 4))
+
+		lru.PutWithTTL("huge", make([]byte, 11), 0)
+
+		assert.False(t, lru.Contains("huge"))
+		assert.Equal(t, 1, lru.Len())
+		assert.Equal(t, int64(4), lru.Cost())
+	})
+
+	t.Run("should release the cost of deleted entries", func(t *testing.T) {
+		lru := NewLRUCacheWithCost(10, byteSize)
+		lru.Put("a", make([]byte, 4))
+
+		lru.Delete("a")
+
+		assert.Zero(t, lru.Cost())
+	})
+}
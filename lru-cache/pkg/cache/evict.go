@@ -0,0 +1,53 @@
+package cache
+
+// EvictionReason tells why an entry left the cache.
+type EvictionReason int
+
+const (
+	// EvictedCapacity means the entry was the least recently used one when
+	// room for a new entry was needed.
+	EvictedCapacity EvictionReason = iota
+	// EvictedExpired means the entry's TTL ran out.
+	EvictedExpired
+	// EvictedDeleted means the entry was removed with Delete.
+	EvictedDeleted
+	// EvictedReplaced means the entry's value was overwritten by Put. The
+	// callback receives the old value.
+	EvictedReplaced
+
+	evictionReasons = iota
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	case EvictedDeleted:
+		return "deleted"
+	case EvictedReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// WithOnEvict registers a function that is called for every entry that
+// leaves the cache, e.g. to flush or close the value.
+//
+// The function is called after the cache lock has been released, on the
+// goroutine whose call caused the eviction (the janitor's goroutine for
+// background expiry). It may therefore call back into the cache, but other
+// goroutines may already have changed the cache by the time it runs.
+func WithOnEvict[K comparable, V any](onEvict func(key K, value V, reason EvictionReason)) Option[K, V] {
+	return func(lru *LRUCache[K, V]) {
+		lru.onEvict = onEvict
+	}
+}
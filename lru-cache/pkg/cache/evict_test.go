@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+)
+
+type evicted struct {
+	key    string
+	value  int
+	reason EvictionReason
+}
+
+func TestOnEvict(t *testing.T) {
+	newCache := func(capacity int, opts ...Option[string, int]) (*LRUCache[string, int], *[]evicted) {
+		var got []evicted
+		opts = append(opts, WithOnEvict(func(key string, value int, reason EvictionReason) {
+			got = append(got, evicted{key, value, reason})
+		}))
+
+		return NewLRUCache(capacity, opts...), &got
+	}
+
+	t.Run("should report capacity evictions", func(t *testing.T) {
+		lru, got := newCache(1)
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+
+		assert.Equal(t, []evicted{{"a", 1, EvictedCapacity}}, *got)
+	})
+
+	t.Run("should report expired entries", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru, got := newCache(2, WithClock[string, int](clock))
+		lru.PutWithTTL("a", 1, time.Second)
+		lru.PutWithTTL("b", 2, time.Second)
+
+		clock.Advance(time.Second)
+		lru.Get("a")
+		lru.RemoveExpired()
+
+		assert.Equal(t, []evicted{{"a", 1, EvictedExpired}, {"b", 2, EvictedExpired}}, *got)
+	})
+
+	t.Run("should report deleted and replaced entries", func(t *testing.T) {
+		lru, got := newCache(2)
+		lru.Put("a", 1)
+		lru.Put("a", 2)
+
+		assert.True(t, lru.Delete("a"))
+		assert.False(t, lru.Delete("a"))
+
+		assert.Equal(t, []evicted{{"a", 1, EvictedReplaced}, {"a", 2, EvictedDeleted}}, *got)
+	})
+
+	t.Run("should call the callback outside of the lock", func(t *testing.T) {
+		var lru *LRUCache[string, int]
+		lru = NewLRUCache(1, WithOnEvict(func(key string, value int, reason EvictionReason) {
+			// Would deadlock if the callback ran while the lock is held.
+			lru.Len()
+		}))
+
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+	})
+
+	t.Run("should name eviction reasons", func(t *testing.T) {
+		assert.Equal(t, "capacity", EvictedCapacity.String())
+		assert.Equal(t, "replaced", EvictedReplaced.String())
+	})
+}
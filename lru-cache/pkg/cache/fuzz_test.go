@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// modelEntry is an entry of modelLRU.
+type modelEntry struct {
+	key, value int
+}
+
+// modelLRU is the simplest possible LRU cache: a slice ordered from the
+// most to the least recently used entry. LRUCache must behave exactly like
+// it.
+type modelLRU struct {
+	capacity int
+	entries  []modelEntry
+}
+
+func (m *modelLRU) index(key int) int {
+	return slices.IndexFunc(m.entries, func(e modelEntry) bool { return e.key == key })
+}
+
+func (m *modelLRU) Get(key int) (int, bool) {
+	i := m.index(key)
+	if i < 0 {
+		return 0, false
+	}
+
+	e := m.entries[i]
+	m.entries = slices.Insert(slices.Delete(m.entries, i, i+1), 0, e)
+
+	return e.value, true
+}
+
+func (m *modelLRU) Peek(key int) (int, bool) {
+	if i := m.index(key); i >= 0 {
+		return m.entries[i].value, true
+	}
+
+	return 0, false
+}
+
+func (m *modelLRU) Put(key, value int) {
+	if i := m.index(key); i >= 0 {
+		m.entries = slices.Delete(m.entries, i, i+1)
+	} else if len(m.entries) == m.capacity {
+		m.entries = m.entries[:len(m.entries)-1]
+	}
+
+	m.entries = slices.Insert(m.entries, 0, modelEntry{key: key, value: value})
+}
+
+func (m *modelLRU) Delete(key int) bool {
+	i := m.index(key)
+	if i < 0 {
+		return false
+	}
+
+	m.entries = slices.Delete(m.entries, i, i+1)
+
+	return true
+}
+
+// checkConsistency verifies the internal structure of the cache against the
+// model: the list is well linked in both directions, every node is in the
+// lookup map and vice versa, size is accurate and the order of the entries
+// is the model's.
+func checkConsistency(lru *LRUCache[int, int], m *modelLRU) error {
+	if lru.head.prev != nil || lru.tail.next != nil {
+		return fmt.Errorf("sentinels are linked outwards")
+	}
+
+	var forward []modelEntry
+	for node := lru.head.next; node != lru.tail; node = node.next {
+		if node == nil {
+			return fmt.Errorf("list ends before tail")
+		}
+		if node.next.prev != node || node.prev.next != node {
+			return fmt.Errorf("links around key %d are inconsistent", node.key)
+		}
+		if lru.lookupMap[node.key] != node {
+			return fmt.Errorf("lookup map does not point to the node of key %d", node.key)
+		}
+		if len(forward) > lru.capacity {
+			return fmt.Errorf("list is longer than the capacity, it may contain a cycle")
+		}
+
+		forward = append(forward, modelEntry{key: node.key, value: node.value})
+	}
+
+	var backward int
+	for node := lru.tail.prev; node != lru.head; node = node.prev {
+		if backward++; backward > len(forward) {
+			return fmt.Errorf("list is longer backwards than forwards")
+		}
+	}
+
+	switch {
+	case backward != len(forward):
+		return fmt.Errorf("list has %d nodes forwards but %d backwards", len(forward), backward)
+	case lru.size != len(forward):
+		return fmt.Errorf("size is %d but the list has %d nodes", lru.size, len(forward))
+	case len(lru.lookupMap) != len(forward):
+		return fmt.Errorf("lookup map has %d entries but the list has %d nodes", len(lru.lookupMap), len(forward))
+	case lru.size > lru.capacity:
+		return fmt.Errorf("size %d exceeds capacity %d", lru.size, lru.capacity)
+	case !slices.Equal(forward, m.entries):
+		return fmt.Errorf("cache holds %v, model holds %v", forward, m.entries)
+	}
+
+	return nil
+}
+
+// FuzzLRUCache applies random operations to an LRUCache and to modelLRU and
+// compares them after every operation. Each operation is encoded in three
+// bytes: the operation, the key and the value.
+func FuzzLRUCache(f *testing.F) {
+	const (
+		opPut = iota
+		opGet
+		opDelete
+		opPeek
+		ops
+	)
+
+	// Capacity 1, updating the head and deleting the only entry.
+	f.Add(uint8(1), []byte{opPut, 1, 1, opPut, 1, 2, opGet, 1, 0, opPut, 2, 3, opDelete, 2, 0, opGet, 2, 0})
+	// Filling the cache and touching the least recently used entry before
+	// adding another one.
+	f.Add(uint8(3), []byte{opPut, 1, 1, opPut, 2, 2, opPut, 3, 3, opGet, 1, 0, opPut, 4, 4, opPeek, 2, 0, opGet, 3, 0})
+	// Deleting from the middle, the head and the tail.
+	f.Add(uint8(4), []byte{opPut, 1, 1, opPut, 2, 2, opPut, 3, 3, opDelete, 2, 0, opDelete, 3, 0, opDelete, 1, 0, opPut, 1, 1})
+
+	f.Fuzz(func(t *testing.T, capacity uint8, program []byte) {
+		capacity = capacity%8 + 1
+		lru := NewLRUCache[int, int](int(capacity))
+		m := &modelLRU{capacity: int(capacity)}
+
+		for i := 0; i+2 < len(program); i += 3 {
+			op, key, value := program[i]%ops, int(program[i+1]%16), int(program[i+2])
+
+			var step string
+			switch op {
+			case opPut:
+				step = fmt.Sprintf("Put(%d, %d)", key, value)
+				lru.Put(key, value)
+				m.Put(key, value)
+			case opGet:
+				step = fmt.Sprintf("Get(%d)", key)
+				gotValue, gotFound := lru.Get(key)
+				wantValue, wantFound := m.Get(key)
+				if gotValue != wantValue || gotFound != wantFound {
+					t.Fatalf("%s = %d, %t, want %d, %t", step, gotValue, gotFound, wantValue, wantFound)
+				}
+			case opDelete:
+				step = fmt.Sprintf("Delete(%d)", key)
+				if got, want := lru.Delete(key), m.Delete(key); got != want {
+					t.Fatalf("%s = %t, want %t", step, got, want)
+				}
+			case opPeek:
+				step = fmt.Sprintf("Peek(%d)", key)
+				gotValue, gotFound := lru.Peek(key)
+				wantValue, wantFound := m.Peek(key)
+				if gotValue != wantValue || gotFound != wantFound {
+					t.Fatalf("%s = %d, %t, want %d, %t", step, gotValue, gotFound, wantValue, wantFound)
+				}
+			}
+
+			if err := checkConsistency(lru, m); err != nil {
+				t.Fatalf("after %s: %v", step, err)
+			}
+			if got, want := lru.Len(), len(m.entries); got != want {
+				t.Fatalf("after %s: Len() = %d, want %d", step, got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// lfuAgingFactor controls how often the access counts of an LFUCache are
+// halved: once every lfuAgingFactor * capacity accesses.
+const lfuAgingFactor = 10
+
+type lfuEntry[K comparable, V any] struct {
+	key   K
+	value V
+	// frequency counts the accesses, lastUsed breaks ties between entries
+	// with the same frequency in favour of the more recently used one.
+	frequency uint64
+	lastUsed  uint64
+	index     int
+}
+
+// LFUCache evicts the least frequently used entry once its capacity is
+// reached. Access counts are halved periodically so that entries that were
+// popular long ago eventually make room for new ones.
+//
+// LFUCache is safe for concurrent use.
+type LFUCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	items    map[K]*lfuEntry[K, V]
+	entries  lfuHeap[K, V]
+	capacity int
+	clock    uint64
+	accesses int
+}
+
+// NewLFUCache panics if capacity is less than 1.
+func NewLFUCache[K comparable, V any](capacity int) *LFUCache[K, V] {
+	checkCapacity(capacity)
+
+	return &LFUCache[K, V]{
+		items:    make(map[K]*lfuEntry[K, V]),
+		capacity: capacity,
+	}
+}
+
+func (c *LFUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, found := c.items[key]; found {
+		c.touch(e)
+
+		return e.value, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+func (c *LFUCache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, found := c.items[key]; found {
+		e.value = value
+		c.touch(e)
+
+		return
+	}
+
+	if len(c.items) >= c.capacity {
+		evicted := heap.Pop(&c.entries).(*lfuEntry[K, V])
+		delete(c.items, evicted.key)
+	}
+
+	c.clock++
+	e := &lfuEntry[K, V]{key: key, value: value, frequency: 1, lastUsed: c.clock}
+	heap.Push(&c.entries, e)
+	c.items[key] = e
+	c.age()
+}
+
+func (c *LFUCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, found := c.items[key]
+	if found {
+		heap.Remove(&c.entries, e.index)
+		delete(c.items, key)
+	}
+
+	return found
+}
+
+func (c *LFUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+func (c *LFUCache[K, V]) touch(e *lfuEntry[K, V]) {
+	c.clock++
+	e.frequency++
+	e.lastUsed = c.clock
+	heap.Fix(&c.entries, e.index)
+	c.age()
+}
+
+// age halves all access counts once enough accesses have happened.
+func (c *LFUCache[K, V]) age() {
+	c.accesses++
+	if c.accesses < lfuAgingFactor*max(c.capacity, 1) {
+		return
+	}
+
+	c.accesses = 0
+	for _, e := range c.entries {
+		e.frequency /= 2
+	}
+	heap.Init(&c.entries)
+}
+
+// lfuHeap is a min-heap of entries ordered by frequency and then by recency.
+type lfuHeap[K comparable, V any] []*lfuEntry[K, V]
+
+func (h lfuHeap[K, V]) Len() int { return len(h) }
+
+func (h lfuHeap[K, V]) Less(i, j int) bool {
+	if h[i].frequency != h[j].frequency {
+		return h[i].frequency < h[j].frequency
+	}
+
+	return h[i].lastUsed < h[j].lastUsed
+}
+
+func (h lfuHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap[K, V]) Push(x any) {
+	e := x.(*lfuEntry[K, V])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+
+	return e
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrLoaderPanicked is returned by LoadingCache.Get if the loader panicked.
+var ErrLoaderPanicked = errors.New("cache: loader panicked")
+
+// Loader fetches the value for a key that is missing from the cache, e.g.
+// from a database or another service.
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// LoadingOption configures a LoadingCache.
+type LoadingOption[K comparable, V any] func(*LoadingCache[K, V])
+
+// WithNegativeCaching remembers loader errors for the given duration, so
+// that keys that fail to load do not hit the backend on every Get.
+func WithNegativeCaching[K comparable, V any](ttl time.Duration) LoadingOption[K, V] {
+	return func(c *LoadingCache[K, V]) {
+		c.negativeTTL = ttl
+	}
+}
+
+// load is a loader call that is in flight. Goroutines asking for the same
+// key wait for done and then share its result.
+type load[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// LoadingCache populates an LRUCache through a Loader. Concurrent misses for
+// the same key share a single call of the loader.
+//
+// LoadingCache is safe for concurrent use.
+type LoadingCache[K comparable, V any] struct {
+	cache  *LRUCache[K, V]
+	loader Loader[K, V]
+
+	mu       sync.Mutex
+	inFlight map[K]*load[V]
+
+	negativeTTL time.Duration
+	failures    *LRUCache[K, error]
+}
+
+// NewLoadingCache wraps the cache so that missing keys are loaded through
+// the loader.
+func NewLoadingCache[K comparable, V any](cache *LRUCache[K, V], loader Loader[K, V], opts ...LoadingOption[K, V]) *LoadingCache[K, V] {
+	c := &LoadingCache[K, V]{
+		cache:    cache,
+		loader:   loader,
+		inFlight: make(map[K]*load[V]),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.negativeTTL > 0 {
+		c.failures = NewLRUCache(cache.capacity, WithClock[K, error](cache.clock))
+	}
+
+	return c
+}
+
+// Get returns the cached value for key, loading it if it is missing. If a
+// load for the key is already in flight, Get waits for its result instead of
+// calling the loader again.
+//
+// The loader runs on a context that carries the values of the ctx of the
+// Get that started it but is never cancelled, so that one caller giving up
+// does not fail the load for everybody else. If ctx is done before the load
+// finishes, Get returns ctx.Err() and the load goes on in the background.
+func (c *LoadingCache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	if value, found := c.cache.Get(key); found {
+		return value, nil
+	}
+
+	if c.failures != nil {
+		if err, found := c.failures.Get(key); found {
+			var zero V
+
+			return zero, err
+		}
+	}
+
+	c.mu.Lock()
+	l, found := c.inFlight[key]
+	if !found {
+		// Loads store their result while holding c.mu, so one that finished
+		// since the lookups above is seen here.
+		if value, found, err := c.peek(key); found {
+			c.mu.Unlock()
+
+			return value, err
+		}
+
+		l = &load[V]{done: make(chan struct{})}
+		c.inFlight[key] = l
+		go c.load(context.WithoutCancel(ctx), key, l)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return l.value, l.err
+	case <-ctx.Done():
+		var zero V
+
+		return zero, ctx.Err()
+	}
+}
+
+// peek returns the cached value or the remembered error for key without
+// counting the lookup.
+func (c *LoadingCache[K, V]) peek(key K) (V, bool, error) {
+	if value, found := c.cache.Peek(key); found {
+		return value, true, nil
+	}
+
+	var zero V
+	if c.failures != nil {
+		if err, found := c.failures.Peek(key); found {
+			return zero, true, err
+		}
+	}
+
+	return zero, false, nil
+}
+
+// load calls the loader and publishes its result to the waiting callers. A
+// panicking loader fails the load instead of crashing the program.
+func (c *LoadingCache[K, V]) load(ctx context.Context, key K, l *load[V]) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.err = fmt.Errorf("%w: %v", ErrLoaderPanicked, r)
+		}
+
+		c.finish(key, l)
+	}()
+
+	l.value, l.err = c.loader(ctx, key)
+}
+
+// finish stores the result of the load, unless the key was invalidated while
+// it was in flight, and wakes up the waiting callers.
+func (c *LoadingCache[K, V]) finish(key K, l *load[V]) {
+	c.mu.Lock()
+	if c.inFlight[key] == l {
+		delete(c.inFlight, key)
+
+		if l.err == nil {
+			// A value too large to cache is still returned to the callers.
+			c.cache.Put(key, l.value)
+		} else if c.failures != nil && !errors.Is(l.err, context.Canceled) && !errors.Is(l.err, context.DeadlineExceeded) {
+			// Timeouts say nothing about the key, so they are not remembered.
+			c.failures.PutWithTTL(key, l.err, c.negativeTTL)
+		}
+	}
+	c.mu.Unlock()
+
+	close(l.done)
+}
+
+// Invalidate removes the cached value and any remembered error for key, so
+// that the next Get loads it again. A load that is in flight may return a
+// value from before the invalidation to the callers already waiting for it,
+// but its result is not cached and later calls of Get start a new load.
+func (c *LoadingCache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.inFlight, key)
+	c.cache.Delete(key)
+	if c.failures != nil {
+		c.failures.Delete(key)
+	}
+}
@@ -0,0 +1,255 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadingCache(t *testing.T) {
+	t.Run("should load missing keys once and serve them from the cache afterwards", func(t *testing.T) {
+		var calls atomic.Int32
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			calls.Add(1)
+			return len(key), nil
+		})
+
+		for range 3 {
+			value, err := c.Get(context.Background(), "abc")
+			require.NoError(t, err)
+			assert.Equal(t, 3, value)
+		}
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should share one load between concurrent misses for the same key", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, nil
+		})
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				value, err := c.Get(context.Background(), "key")
+				assert.NoError(t, err)
+				assert.Equal(t, 42, value)
+			}()
+		}
+
+		// Give the goroutines time to pile up behind the first load.
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should return loader errors without caching them by default", func(t *testing.T) {
+		var calls atomic.Int32
+		errBackend := errors.New("backend down")
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			calls.Add(1)
+			return 0, errBackend
+		})
+
+		_, err := c.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, errBackend)
+		_, err = c.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, errBackend)
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should remember loader errors for the negative caching period", func(t *testing.T) {
+		var calls atomic.Int32
+		clock := cachetest.NewClock()
+		errBackend := errors.New("backend down")
+		c := NewLoadingCache(NewLRUCache(2, WithClock[string, int](clock)), func(ctx context.Context, key string) (int, error) {
+			calls.Add(1)
+			return 0, errBackend
+		}, WithNegativeCaching[string, int](time.Minute))
+
+		_, err := c.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, errBackend)
+		_, err = c.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, errBackend)
+		assert.Equal(t, int32(1), calls.Load())
+
+		clock.Advance(time.Minute)
+		c.Get(context.Background(), "key")
+		assert.Equal(t, int32(2), calls.Load())
+
+		c.Invalidate("key")
+		c.Get(context.Background(), "key")
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("should stop waiting for another load when the context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			<-release
+			return 1, nil
+		})
+
+		go c.Get(context.Background(), "key")
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := c.Get(ctx, "key")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should finish the load for others when the caller that started it gives up", func(t *testing.T) {
+		release := make(chan struct{})
+		var calls atomic.Int32
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			calls.Add(1)
+			<-release
+			return 42, ctx.Err()
+		}, WithNegativeCaching[string, int](time.Minute))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := make(chan error)
+		go func() {
+			_, err := c.Get(ctx, "key")
+			first <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		waiter := make(chan int)
+		go func() {
+			value, err := c.Get(context.Background(), "key")
+			assert.NoError(t, err)
+			waiter <- value
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-first, context.Canceled)
+
+		close(release)
+		assert.Equal(t, 42, <-waiter)
+
+		value, err := c.Get(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should not remember context errors of the loader", func(t *testing.T) {
+		var calls atomic.Int32
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, fmt.Errorf("query: %w", context.DeadlineExceeded)
+			}
+			return 1, nil
+		}, WithNegativeCaching[string, int](time.Minute))
+
+		_, err := c.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		value, err := c.Get(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("should fail the load instead of hanging when the loader panics", func(t *testing.T) {
+		var calls atomic.Int32
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			if calls.Add(1) == 1 {
+				panic("boom")
+			}
+			return 1, nil
+		})
+
+		_, err := c.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, ErrLoaderPanicked)
+		assert.ErrorContains(t, err, "boom")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		value, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("should not load again after a concurrent load has stored its value", func(t *testing.T) {
+		for i := range 100 {
+			var calls atomic.Int32
+			c := NewLoadingCache(NewLRUCache[int, int](2), func(ctx context.Context, key int) (int, error) {
+				calls.Add(1)
+				return key, nil
+			})
+
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					value, err := c.Get(context.Background(), i)
+					assert.NoError(t, err)
+					assert.Equal(t, i, value)
+				}()
+			}
+			wg.Wait()
+
+			require.Equal(t, int32(1), calls.Load(), "iteration %d", i)
+		}
+	})
+
+	t.Run("should not cache the result of a load that was invalidated while in flight", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		c := NewLoadingCache(NewLRUCache[string, int](2), func(ctx context.Context, key string) (int, error) {
+			n := calls.Add(1)
+			if n == 1 {
+				<-release
+			}
+			return int(n), nil
+		})
+
+		stale := make(chan int)
+		go func() {
+			value, err := c.Get(context.Background(), "key")
+			assert.NoError(t, err)
+			stale <- value
+		}()
+
+		require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+		c.Invalidate("key")
+
+		value, err := c.Get(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+
+		close(release)
+		assert.Equal(t, 1, <-stale)
+
+		value, err = c.Get(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
@@ -0,0 +1,481 @@
+// Package cache provides an in-memory cache that evicts the least recently
+// used entry once its capacity is reached.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	// expiresAt is the zero time for entries that never expire.
+	expiresAt time.Time
+	cost      int64
+	prev      *entry[K, V]
+	next      *entry[K, V]
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// LRUCache maps keys to values and holds at most capacity entries. Entries
+// are kept in a doubly linked list between two sentinel nodes, ordered from
+// the most recently used after head to the least recently used before tail.
+//
+// LRUCache is safe for concurrent use. Since even Get reorders the list, all
+// operations are serialized by a single lock; use ShardedLRUCache if that
+// lock becomes a bottleneck.
+type LRUCache[K comparable, V any] struct {
+	mu        sync.Mutex
+	lookupMap map[K]*entry[K, V]
+	head      *entry[K, V]
+	tail      *entry[K, V]
+	capacity  int
+	size      int
+	// cost is nil unless the cache is bounded by maxCost instead of
+	// capacity.
+	cost      func(key K, value V) int64
+	maxCost   int64
+	totalCost int64
+	clock     Clock
+	onEvict   func(key K, value V, reason EvictionReason)
+	// evicted collects the evictions to report once the lock is released.
+	evicted []eviction[K, V]
+	stats   counters
+}
+
+func checkCapacity(capacity int) {
+	if capacity < 1 {
+		panic(fmt.Sprintf("cache: capacity must be positive, got %d", capacity))
+	}
+}
+
+// ErrTooLarge is returned when a single value costs more than the budget of
+// the cache.
+var ErrTooLarge = errors.New("value exceeds the cache budget")
+
+// NewLRUCache creates a cache that holds at most capacity entries. It panics
+// if capacity is less than 1.
+func NewLRUCache[K comparable, V any](capacity int, opts ...Option[K, V]) *LRUCache[K, V] {
+	checkCapacity(capacity)
+
+	lru := &LRUCache[K, V]{
+		lookupMap: make(map[K]*entry[K, V]),
+		capacity:  capacity,
+		size:      0,
+		head:      &entry[K, V]{},
+		tail:      &entry[K, V]{},
+		clock:     SystemClock(),
+	}
+
+	lru.head.next = lru.tail
+	lru.tail.prev = lru.head
+
+	for _, opt := range opts {
+		opt(lru)
+	}
+
+	return lru
+}
+
+// NewLRUCacheWithCost creates a cache that is bounded by the total cost of
+// its entries instead of their number. The cost function is called once per
+// Put, e.g. to return the size of the value in bytes, and must not return a
+// negative cost. Least recently used entries are evicted until the total
+// cost is at most maxCost. It panics if maxCost is less than 1.
+func NewLRUCacheWithCost[K comparable, V any](maxCost int64, cost func(key K, value V) int64, opts ...Option[K, V]) *LRUCache[K, V] {
+	if maxCost < 1 {
+		panic(fmt.Sprintf("cache: budget must be positive, got %d", maxCost))
+	}
+
+	lru := NewLRUCache(math.MaxInt, opts...)
+	lru.cost = cost
+	lru.maxCost = maxCost
+
+	return lru
+}
+
+// Cost returns the total cost of all entries. It is always zero for caches
+// created by NewLRUCache.
+func (lru *LRUCache[K, V]) Cost() int64 {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return lru.totalCost
+}
+
+// Print returns the entries from the most to the least recently used, e.g.
+// "2: b -> 1: a".
+func (lru *LRUCache[K, V]) Print() string {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	builder := strings.Builder{}
+
+	for currentNode := lru.head.next; currentNode != lru.tail; currentNode = currentNode.next {
+		builder.WriteString(fmt.Sprintf("%v: %v", currentNode.key, currentNode.value))
+
+		if currentNode.next != lru.tail {
+			builder.WriteString(" -> ")
+		}
+	}
+
+	return builder.String()
+}
+
+// Get returns the value stored for key and marks it as most recently used.
+// The boolean reports whether the key was found. Expired entries are removed
+// and reported as missing.
+func (lru *LRUCache[K, V]) Get(key K) (V, bool) {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	if node, found := lru.lookupMap[key]; found {
+		if node.expired(lru.clock.Now()) {
+			lru.deleteNode(node, EvictedExpired)
+		} else {
+			lru.moveToFront(node)
+			lru.stats.hits.Add(1)
+
+			return node.value, true
+		}
+	}
+
+	lru.stats.misses.Add(1)
+
+	var zero V
+
+	return zero, false
+}
+
+// Put stores a value that never expires. A value whose cost exceeds the
+// budget of a cache created by NewLRUCacheWithCost is not stored; use TryPut
+// to find out.
+func (lru *LRUCache[K, V]) Put(key K, value V) {
+	_ = lru.TryPut(key, value)
+}
+
+// PutWithTTL stores a value that expires after the given duration. A
+// non-positive ttl means the value never expires. Like Put, it drops values
+// that exceed the budget.
+func (lru *LRUCache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	_ = lru.TryPutWithTTL(key, value, ttl)
+}
+
+// TryPut stores a value like Put. It returns ErrTooLarge, and leaves the
+// cache unchanged, if the value's cost exceeds the cache's budget.
+func (lru *LRUCache[K, V]) TryPut(key K, value V) error {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	return lru.put(key, value, time.Time{})
+}
+
+// TryPutWithTTL stores a value like PutWithTTL. Like TryPut, it returns
+// ErrTooLarge if the value's cost exceeds the cache's budget.
+func (lru *LRUCache[K, V]) TryPutWithTTL(key K, value V, ttl time.Duration) error {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = lru.clock.Now().Add(ttl)
+	}
+
+	return lru.put(key, value, expiresAt)
+}
+
+// PutWithExpiry stores a value that expires at the given time, e.g. one
+// restored from another store that kept its expiry time. A zero expiresAt
+// means the value never expires. Like Put, it drops values that exceed the
+// budget.
+func (lru *LRUCache[K, V]) PutWithExpiry(key K, value V, expiresAt time.Time) {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	_ = lru.put(key, value, expiresAt)
+}
+
+// Delete removes the entry for key and reports whether it was present.
+func (lru *LRUCache[K, V]) Delete(key K) bool {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	node, found := lru.lookupMap[key]
+	if found {
+		lru.deleteNode(node, EvictedDeleted)
+	}
+
+	return found
+}
+
+// Peek returns the value stored for key without marking it as used.
+func (lru *LRUCache[K, V]) Peek(key K) (V, bool) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if node, found := lru.lookupMap[key]; found && !node.expired(lru.clock.Now()) {
+		return node.value, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// Contains reports whether a value is stored for key without marking it as
+// used.
+func (lru *LRUCache[K, V]) Contains(key K) bool {
+	_, found := lru.Peek(key)
+
+	return found
+}
+
+// Keys returns the keys from the most to the least recently used.
+func (lru *LRUCache[K, V]) Keys() []K {
+	keys := make([]K, 0, lru.Len())
+	for key := range lru.All() {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Values returns the values from the most to the least recently used.
+func (lru *LRUCache[K, V]) Values() []V {
+	values := make([]V, 0, lru.Len())
+	for _, value := range lru.All() {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// All returns an iterator over the entries from the most to the least
+// recently used. It iterates over a copy taken when iteration starts, so the
+// loop body may use the cache; iterating does not mark entries as used.
+func (lru *LRUCache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		lru.mu.Lock()
+		now := lru.clock.Now()
+		entries := make([]*entry[K, V], 0, lru.size)
+		for node := lru.head.next; node != lru.tail; node = node.next {
+			if !node.expired(now) {
+				entries = append(entries, &entry[K, V]{key: node.key, value: node.value})
+			}
+		}
+		lru.mu.Unlock()
+
+		for _, e := range entries {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Resize changes the capacity, evicting least recently used entries if the
+// cache holds more than the new capacity. It panics if capacity is less
+// than 1.
+func (lru *LRUCache[K, V]) Resize(capacity int) {
+	checkCapacity(capacity)
+
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	lru.capacity = capacity
+	for lru.size > lru.capacity {
+		lru.popTail()
+	}
+}
+
+// Purge removes all entries. They are reported to the OnEvict callback as
+// deleted.
+func (lru *LRUCache[K, V]) Purge() {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	for lru.size > 0 {
+		lru.deleteNode(lru.tail.prev, EvictedDeleted)
+	}
+}
+
+// RemoveExpired removes all expired entries and returns how many were
+// removed.
+func (lru *LRUCache[K, V]) RemoveExpired() int {
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	now := lru.clock.Now()
+	removed := 0
+
+	for node := lru.head.next; node != lru.tail; {
+		next := node.next
+		if node.expired(now) {
+			lru.deleteNode(node, EvictedExpired)
+			removed++
+		}
+		node = next
+	}
+
+	return removed
+}
+
+// StartJanitor removes expired entries in the background every interval
+// until the returned stop function is called. Without a janitor, expired
+// entries are only removed when they are read or evicted.
+func (lru *LRUCache[K, V]) StartJanitor(interval time.Duration) (stop func()) {
+	return startJanitor(interval, func() { lru.RemoveExpired() })
+}
+
+func (lru *LRUCache[K, V]) put(key K, value V, expiresAt time.Time) error {
+	var cost int64
+	if lru.cost != nil {
+		cost = lru.cost(key, value)
+		if cost > lru.maxCost {
+			return fmt.Errorf("cost %d exceeds budget %d: %w", cost, lru.maxCost, ErrTooLarge)
+		}
+	}
+
+	// Handle update case
+	if node, found := lru.lookupMap[key]; found {
+		lru.recordEviction(node, EvictedReplaced)
+		lru.stats.updates.Add(1)
+		lru.totalCost += cost - node.cost
+		node.value = value
+		node.expiresAt = expiresAt
+		node.cost = cost
+		lru.moveToFront(node)
+		lru.evictOverBudget()
+
+		return nil
+	}
+
+	// If capacity is reached we need to remove the last node before adding a new one
+	if lru.size == lru.capacity {
+		lru.popTail()
+	}
+
+	// Handle add new node case
+	node := &entry[K, V]{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+		cost:      cost,
+	}
+
+	lru.lookupMap[key] = node
+	lru.moveToFront(node)
+	lru.size++
+	lru.stats.insertions.Add(1)
+	lru.totalCost += cost
+	lru.evictOverBudget()
+
+	return nil
+}
+
+// evictOverBudget evicts from the tail until the total cost fits the budget.
+// The most recently stored entry is never evicted since its cost alone is
+// within the budget.
+func (lru *LRUCache[K, V]) evictOverBudget() {
+	for lru.cost != nil && lru.totalCost > lru.maxCost {
+		lru.popTail()
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// that have not been removed yet.
+func (lru *LRUCache[K, V]) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return lru.size
+}
+
+// unlock releases the lock and then reports the evictions collected while it
+// was held, so that the OnEvict callback runs outside of the lock.
+func (lru *LRUCache[K, V]) unlock() {
+	evicted := lru.evicted
+	lru.evicted = nil
+	lru.mu.Unlock()
+
+	for _, e := range evicted {
+		lru.onEvict(e.key, e.value, e.reason)
+	}
+}
+
+func (lru *LRUCache[K, V]) recordEviction(node *entry[K, V], reason EvictionReason) {
+	lru.stats.evictions[reason].Add(1)
+
+	if lru.onEvict != nil {
+		lru.evicted = append(lru.evicted, eviction[K, V]{key: node.key, value: node.value, reason: reason})
+	}
+}
+
+func (lru *LRUCache[K, V]) moveToFront(node *entry[K, V]) {
+	// Remove node from its original spot
+	lru.removeNode(node)
+
+	// Move node after head node
+	node.next = lru.head.next
+	node.prev = lru.head
+	lru.head.next.prev = node
+	lru.head.next = node
+}
+
+func (lru *LRUCache[K, V]) popTail() *entry[K, V] {
+	toPop := lru.tail.prev
+
+	lru.deleteNode(toPop, EvictedCapacity)
+
+	return toPop
+}
+
+// deleteNode removes the node from the list and the lookup map.
+func (lru *LRUCache[K, V]) deleteNode(node *entry[K, V], reason EvictionReason) {
+	lru.recordEviction(node, reason)
+	lru.removeNode(node)
+	delete(lru.lookupMap, node.key)
+	lru.size--
+	lru.totalCost -= node.cost
+}
+
+func (lru *LRUCache[K, V]) removeNode(node *entry[K, V]) {
+	if node.prev == nil || node.next == nil {
+		return
+	}
+
+	node.prev.next = node.next
+	node.next.prev = node.prev
+}
+
+func startJanitor(interval time.Duration, removeExpired func()) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				removeExpired()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
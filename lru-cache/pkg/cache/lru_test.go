@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	t.Run("should return stored values", func(t *testing.T) {
+		lru := NewLRUCache[string, int](2)
+		lru.Put("a", 1)
+
+		value, found := lru.Get("a")
+
+		assert.True(t, found)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("should distinguish missing keys from stored zero or negative values", func(t *testing.T) {
+		lru := NewLRUCache[int, int](2)
+		lru.Put(1, -1)
+
+		value, found := lru.Get(1)
+		assert.True(t, found)
+		assert.Equal(t, -1, value)
+
+		value, found = lru.Get(2)
+		assert.False(t, found)
+		assert.Zero(t, value)
+	})
+
+	t.Run("should evict the least recently used entry", func(t *testing.T) {
+		lru := NewLRUCache[int, string](2)
+		lru.Put(1, "one")
+		lru.Put(2, "two")
+		lru.Get(1)
+		lru.Put(3, "three")
+
+		_, found := lru.Get(2)
+		assert.False(t, found)
+
+		_, found = lru.Get(1)
+		assert.True(t, found)
+		_, found = lru.Get(3)
+		assert.True(t, found)
+	})
+
+	t.Run("should update existing entries without evicting", func(t *testing.T) {
+		lru := NewLRUCache[int, string](2)
+		lru.Put(1, "one")
+		lru.Put(2, "two")
+		lru.Put(1, "uno")
+
+		value, _ := lru.Get(1)
+		assert.Equal(t, "uno", value)
+
+		_, found := lru.Get(2)
+		assert.True(t, found)
+	})
+
+	t.Run("should store struct values", func(t *testing.T) {
+		type session struct {
+			user  string
+			roles []string
+		}
+
+		lru := NewLRUCache[string, session](1)
+		lru.Put("token", session{user: "alice", roles: []string{"admin"}})
+
+		value, found := lru.Get("token")
+		assert.True(t, found)
+		assert.Equal(t, "alice", value.user)
+	})
+}
+
+func TestLRUCacheAPI(t *testing.T) {
+	newCache := func() *LRUCache[int, string] {
+		lru := NewLRUCache[int, string](3)
+		lru.Put(1, "one")
+		lru.Put(2, "two")
+		lru.Put(3, "three")
+
+		return lru
+	}
+
+	t.Run("should peek without changing the recency order", func(t *testing.T) {
+		lru := newCache()
+
+		value, found := lru.Peek(1)
+		assert.True(t, found)
+		assert.Equal(t, "one", value)
+		assert.True(t, lru.Contains(1))
+		assert.False(t, lru.Contains(4))
+
+		lru.Put(4, "four")
+		assert.False(t, lru.Contains(1))
+	})
+
+	t.Run("should list keys and values from the most to the least recently used", func(t *testing.T) {
+		lru := newCache()
+		lru.Get(1)
+
+		assert.Equal(t, []int{1, 3, 2}, lru.Keys())
+		assert.Equal(t, []string{"one", "three", "two"}, lru.Values())
+	})
+
+	t.Run("should iterate over entries and allow stopping early", func(t *testing.T) {
+		lru := newCache()
+
+		var keys []int
+		for key, value := range lru.All() {
+			keys = append(keys, key)
+			assert.NotEmpty(t, value)
+			if len(keys) == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, []int{3, 2}, keys)
+	})
+
+	t.Run("should evict down to a smaller capacity", func(t *testing.T) {
+		lru := newCache()
+
+		lru.Resize(1)
+		assert.Equal(t, []int{3}, lru.Keys())
+
+		lru.Resize(2)
+		lru.Put(4, "four")
+		assert.Equal(t, []int{4, 3}, lru.Keys())
+	})
+
+	t.Run("should purge all entries", func(t *testing.T) {
+		lru := newCache()
+
+		lru.Purge()
+
+		assert.Equal(t, 0, lru.Len())
+		assert.Empty(t, lru.Keys())
+		assert.Equal(t, "", lru.Print())
+
+		lru.Put(5, "five")
+		assert.Equal(t, "5: five", lru.Print())
+	})
+
+	t.Run("should reject capacities below one", func(t *testing.T) {
+		assert.Panics(t, func() { NewLRUCache[int, int](0) })
+		assert.Panics(t, func() { NewLRUCache[int, int](-1) })
+		assert.Panics(t, func() { newCache().Resize(0) })
+		assert.Panics(t, func() { NewShardedLRUCache[int, int](4, 0) })
+
+		_, err := New[int, int](PolicyARC, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("should not print the sentinel nodes", func(t *testing.T) {
+		assert.Equal(t, "3: three -> 2: two -> 1: one", newCache().Print())
+	})
+}
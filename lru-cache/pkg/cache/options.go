@@ -0,0 +1,29 @@
+package cache
+
+import "time"
+
+// Option configures an LRUCache.
+type Option[K comparable, V any] func(*LRUCache[K, V])
+
+// Clock tells the cache the current time. It is used to expire entries.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock returns the Clock caches use unless told otherwise.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock replaces the system clock, e.g. to control expiry in tests.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(lru *LRUCache[K, V]) {
+		lru.clock = clock
+	}
+}
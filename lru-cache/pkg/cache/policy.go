@@ -0,0 +1,54 @@
+package cache
+
+import "fmt"
+
+// Cache is implemented by all eviction policies of this package, so that
+// the policy can be chosen by configuration.
+type Cache[K comparable, V any] interface {
+	// Get returns the value stored for key and whether it was found.
+	Get(key K) (V, bool)
+	// Put stores a value for key, evicting an entry if the cache is full.
+	Put(key K, value V)
+	// Delete removes the entry for key and reports whether it was present.
+	Delete(key K) bool
+	// Len returns the number of entries in the cache.
+	Len() int
+}
+
+var (
+	_ Cache[string, int] = (*LRUCache[string, int])(nil)
+	_ Cache[string, int] = (*ShardedLRUCache[string, int])(nil)
+	_ Cache[string, int] = (*LFUCache[string, int])(nil)
+	_ Cache[string, int] = (*TwoQueueCache[string, int])(nil)
+	_ Cache[string, int] = (*ARCCache[string, int])(nil)
+)
+
+// Policy names an eviction policy.
+type Policy string
+
+const (
+	PolicyLRU Policy = "lru"
+	PolicyLFU Policy = "lfu"
+	Policy2Q  Policy = "2q"
+	PolicyARC Policy = "arc"
+)
+
+// New creates a cache with the given eviction policy and capacity.
+func New[K comparable, V any](policy Policy, capacity int) (Cache[K, V], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("capacity must be positive, got %d", capacity)
+	}
+
+	switch policy {
+	case PolicyLRU:
+		return NewLRUCache[K, V](capacity), nil
+	case PolicyLFU:
+		return NewLFUCache[K, V](capacity), nil
+	case Policy2Q:
+		return NewTwoQueueCache[K, V](capacity), nil
+	case PolicyARC:
+		return NewARCCache[K, V](capacity), nil
+	default:
+		return nil, fmt.Errorf("unknown eviction policy %q", policy)
+	}
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policies = []Policy{PolicyLRU, PolicyLFU, Policy2Q, PolicyARC}
+
+func TestPolicies(t *testing.T) {
+	for _, policy := range policies {
+		t.Run(string(policy)+" should store, update and delete entries", func(t *testing.T) {
+			c, err := New[string, int](policy, 4)
+			require.NoError(t, err)
+
+			c.Put("a", 1)
+			c.Put("a", 2)
+
+			value, found := c.Get("a")
+			assert.True(t, found)
+			assert.Equal(t, 2, value)
+			assert.Equal(t, 1, c.Len())
+
+			assert.True(t, c.Delete("a"))
+			assert.False(t, c.Delete("a"))
+			_, found = c.Get("a")
+			assert.False(t, found)
+			assert.Equal(t, 0, c.Len())
+		})
+
+		t.Run(string(policy)+" should never exceed its capacity", func(t *testing.T) {
+			c, err := New[int, int](policy, 8)
+			require.NoError(t, err)
+
+			rng := rand.New(rand.NewPCG(1, 2))
+			for range 10_000 {
+				key := rng.IntN(32)
+				if rng.IntN(2) == 0 {
+					c.Put(key, key)
+				} else if value, found := c.Get(key); found {
+					require.Equal(t, key, value)
+				}
+				require.LessOrEqual(t, c.Len(), 8)
+			}
+		})
+	}
+
+	t.Run("should reject unknown policies", func(t *testing.T) {
+		_, err := New[string, int]("fifo", 4)
+		assert.Error(t, err)
+	})
+}
+
+func TestScanResistance(t *testing.T) {
+	const capacity = 100
+
+	// survivors returns how many hot keys are still cached after the hot
+	// set was used repeatedly and then a scan over cold keys happened.
+	survivors := func(policy Policy) int {
+		c, _ := New[int, int](policy, capacity)
+
+		for range 5 {
+			for key := range capacity / 2 {
+				if _, found := c.Get(key); !found {
+					c.Put(key, key)
+				}
+			}
+		}
+
+		for key := 1000; key < 1000+capacity*2; key++ {
+			if _, found := c.Get(key); !found {
+				c.Put(key, key)
+			}
+		}
+
+		hits := 0
+		for key := range capacity / 2 {
+			if _, found := c.Get(key); found {
+				hits++
+			}
+		}
+
+		return hits
+	}
+
+	assert.Equal(t, 0, survivors(PolicyLRU), "a scan evicts the whole hot set of an LRU cache")
+	for _, policy := range []Policy{PolicyLFU, Policy2Q, PolicyARC} {
+		assert.Equal(t, capacity/2, survivors(policy), fmt.Sprintf("%s should keep the hot set during a scan", policy))
+	}
+}
+
+// BenchmarkPolicies replays a skewed workload that is interrupted by scans
+// and reports the hit ratio of each policy next to its speed.
+func BenchmarkPolicies(b *testing.B) {
+	const capacity = 1000
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(rng, 1.1, 1, 100_000)
+
+	workload := make([]int, 100_000)
+	for i := range workload {
+		if i%10_000 < 2000 {
+			workload[i] = 1_000_000 + i
+		} else {
+			workload[i] = int(zipf.Uint64())
+		}
+	}
+
+	for _, policy := range policies {
+		b.Run(string(policy), func(b *testing.B) {
+			c, _ := New[int, int](policy, capacity)
+			hits, lookups := 0, 0
+
+			for i := 0; b.Loop(); i++ {
+				key := workload[i%len(workload)]
+				lookups++
+				if _, found := c.Get(key); found {
+					hits++
+				} else {
+					c.Put(key, key)
+				}
+			}
+
+			b.ReportMetric(float64(hits)/float64(lookups), "hit-ratio")
+		})
+	}
+}
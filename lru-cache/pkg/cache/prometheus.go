@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WritePrometheus writes the statistics in the Prometheus text exposition
+// format. Metric names are prefixed with namespace, e.g. "sessions" yields
+// "sessions_cache_hits_total".
+func WritePrometheus(w io.Writer, namespace string, s Stats) error {
+	bw := bufio.NewWriter(w)
+	prefix := namespace + "_cache_"
+
+	counter := func(name, help string, value uint64) {
+		fmt.Fprintf(bw, "# HELP %s%s %s\n", prefix, name, help)
+		fmt.Fprintf(bw, "# TYPE %s%s counter\n", prefix, name)
+		fmt.Fprintf(bw, "%s%s %d\n", prefix, name, value)
+	}
+
+	counter("hits_total", "Number of lookups that found a value.", s.Hits)
+	counter("misses_total", "Number of lookups that found no value.", s.Misses)
+	counter("insertions_total", "Number of values stored under a new key.", s.Insertions)
+	counter("updates_total", "Number of values stored under an existing key.", s.Updates)
+
+	fmt.Fprintf(bw, "# HELP %sevictions_total Number of entries that left the cache.\n", prefix)
+	fmt.Fprintf(bw, "# TYPE %sevictions_total counter\n", prefix)
+	for reason := range EvictionReason(evictionReasons) {
+		fmt.Fprintf(bw, "%sevictions_total{reason=%q} %d\n", prefix, reason.String(), s.Evicted(reason))
+	}
+
+	fmt.Fprintf(bw, "# HELP %shit_ratio Share of lookups that found a value.\n", prefix)
+	fmt.Fprintf(bw, "# TYPE %shit_ratio gauge\n", prefix)
+	fmt.Fprintf(bw, "%shit_ratio %g\n", prefix, s.HitRatio())
+
+	return bw.Flush()
+}
+
+// PrometheusHandler serves the statistics returned by stats in the
+// Prometheus text exposition format, e.g. for a /metrics endpoint.
+func PrometheusHandler(namespace string, stats func() Stats) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WritePrometheus(w, namespace, stats())
+	})
+}
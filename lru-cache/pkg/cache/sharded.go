@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// ShardedLRUCache spreads its keys over several independently locked
+// LRUCaches, selected by a hash of the key, so that goroutines working on
+// different keys rarely wait for each other. Recency and eviction are
+// tracked per shard, so the entry evicted is the least recently used one of
+// its shard rather than of the whole cache.
+type ShardedLRUCache[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*LRUCache[K, V]
+}
+
+// NewShardedLRUCache creates a cache with the given number of shards that
+// holds at least capacity entries in total. The capacity is split evenly
+// between the shards. The options are applied to every shard. It panics if
+// capacity is less than 1.
+func NewShardedLRUCache[K comparable, V any](shards, capacity int, opts ...Option[K, V]) *ShardedLRUCache[K, V] {
+	checkCapacity(capacity)
+
+	shards = max(shards, 1)
+	perShard := max((capacity+shards-1)/shards, 1)
+
+	c := &ShardedLRUCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*LRUCache[K, V], shards),
+	}
+	for i := range c.shards {
+		c.shards[i] = NewLRUCache(perShard, opts...)
+	}
+
+	return c
+}
+
+func (c *ShardedLRUCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Put(key K, value V) {
+	c.shard(key).Put(key, value)
+}
+
+func (c *ShardedLRUCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Peek(key K) (V, bool) {
+	return c.shard(key).Peek(key)
+}
+
+func (c *ShardedLRUCache[K, V]) Contains(key K) bool {
+	return c.shard(key).Contains(key)
+}
+
+// Purge removes all entries from all shards.
+func (c *ShardedLRUCache[K, V]) Purge() {
+	for _, s := range c.shards {
+		s.Purge()
+	}
+}
+
+func (c *ShardedLRUCache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	c.shard(key).PutWithTTL(key, value, ttl)
+}
+
+// RemoveExpired removes all expired entries from all shards and returns how
+// many were removed.
+func (c *ShardedLRUCache[K, V]) RemoveExpired() int {
+	removed := 0
+	for _, s := range c.shards {
+		removed += s.RemoveExpired()
+	}
+
+	return removed
+}
+
+// StartJanitor removes expired entries of all shards in the background every
+// interval until the returned stop function is called.
+func (c *ShardedLRUCache[K, V]) StartJanitor(interval time.Duration) (stop func()) {
+	return startJanitor(interval, func() { c.RemoveExpired() })
+}
+
+// Stats returns the sum of the statistics of all shards.
+func (c *ShardedLRUCache[K, V]) Stats() Stats {
+	var total Stats
+	for _, s := range c.shards {
+		total = total.add(s.Stats())
+	}
+
+	return total
+}
+
+// Len returns the number of entries in all shards.
+func (c *ShardedLRUCache[K, V]) Len() int {
+	total := 0
+	for _, s := range c.shards {
+		total += s.Len()
+	}
+
+	return total
+}
+
+func (c *ShardedLRUCache[K, V]) shard(key K) *LRUCache[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The concurrency tests are meant to be run with the race detector:
+//
+//	go test -race ./...
+
+func TestConcurrentAccess(t *testing.T) {
+	const goroutines = 16
+	const operations = 1000
+
+	caches := map[string]interface {
+		Get(int) (int, bool)
+		Put(int, int)
+		Len() int
+	}{
+		"LRUCache":        NewLRUCache[int, int](64),
+		"ShardedLRUCache": NewShardedLRUCache[int, int](8, 64),
+	}
+
+	for name, c := range caches {
+		t.Run(name+" should stay consistent under concurrent reads and writes", func(t *testing.T) {
+			var wg sync.WaitGroup
+			for g := range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					for i := range operations {
+						key := (g*operations + i) % 128
+						c.Put(key, key*2)
+						if value, found := c.Get(key); found {
+							assert.Equal(t, key*2, value)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.LessOrEqual(t, c.Len(), 64)
+		})
+	}
+}
+
+func TestShardedLRUCache(t *testing.T) {
+	t.Run("should split the capacity between the shards", func(t *testing.T) {
+		c := NewShardedLRUCache[string, int](4, 10)
+
+		assert.Len(t, c.shards, 4)
+		for _, s := range c.shards {
+			assert.Equal(t, 3, s.capacity)
+		}
+	})
+
+	t.Run("should always select the same shard for a key", func(t *testing.T) {
+		c := NewShardedLRUCache[string, int](4, 100)
+
+		for i := range 20 {
+			c.Put(fmt.Sprintf("key-%d", i), i)
+		}
+
+		for i := range 20 {
+			value, found := c.Get(fmt.Sprintf("key-%d", i))
+			assert.True(t, found)
+			assert.Equal(t, i, value)
+		}
+		assert.Equal(t, 20, c.Len())
+	})
+}
+
+func BenchmarkParallelGet(b *testing.B) {
+	benchmarks := map[string]interface {
+		Get(int) (int, bool)
+		Put(int, int)
+	}{
+		"LRUCache":        NewLRUCache[int, int](1024),
+		"ShardedLRUCache": NewShardedLRUCache[int, int](16, 1024),
+	}
+
+	for name, c := range benchmarks {
+		for i := range 1024 {
+			c.Put(i, i)
+		}
+
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Get(i % 1024)
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshot file layout, all integers big endian:
+//
+//	header:  magic "LRUS" | version uint16 | entry count uint64 | crc32 of the preceding header bytes
+//	entry:   expiresAt int64 (Unix nanoseconds, 0 = never) | key length uint32 | key | value length uint32 | value | crc32 of the preceding entry bytes
+//
+// Entries are written from the least to the most recently used, so that
+// loading them in file order restores the recency order.
+const (
+	snapshotMagic   = "LRUS"
+	snapshotVersion = 1
+)
+
+var (
+	ErrCorruptSnapshot            = errors.New("corrupt cache snapshot")
+	ErrUnsupportedSnapshotVersion = errors.New("unsupported cache snapshot version")
+)
+
+var snapshotTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Codec turns keys or values into bytes and back for snapshots.
+type Codec[T any] interface {
+	Encode(v T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// GobCodec encodes values with encoding/gob.
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Encode(v T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+
+	return v, err
+}
+
+type snapshotEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// WriteSnapshot writes all entries that have not expired, including their
+// expiry times, to w. The cache is only locked while the entries are
+// collected, not while they are encoded and written.
+func (lru *LRUCache[K, V]) WriteSnapshot(w io.Writer, keys Codec[K], values Codec[V]) error {
+	lru.mu.Lock()
+	now := lru.clock.Now()
+	entries := make([]snapshotEntry[K, V], 0, lru.size)
+	for node := lru.tail.prev; node != lru.head; node = node.prev {
+		if !node.expired(now) {
+			entries = append(entries, snapshotEntry[K, V]{key: node.key, value: node.value, expiresAt: node.expiresAt})
+		}
+	}
+	lru.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	header := []byte(snapshotMagic)
+	header = binary.BigEndian.AppendUint16(header, snapshotVersion)
+	header = binary.BigEndian.AppendUint64(header, uint64(len(entries)))
+	header = binary.BigEndian.AppendUint32(header, crc32.Checksum(header, snapshotTable))
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	var record []byte
+	for _, e := range entries {
+		key, err := keys.Encode(e.key)
+		if err != nil {
+			return fmt.Errorf("encode key: %w", err)
+		}
+		value, err := values.Encode(e.value)
+		if err != nil {
+			return fmt.Errorf("encode value: %w", err)
+		}
+
+		var expiresAt int64
+		if !e.expiresAt.IsZero() {
+			expiresAt = e.expiresAt.UnixNano()
+		}
+
+		record = binary.BigEndian.AppendUint64(record[:0], uint64(expiresAt))
+		record = binary.BigEndian.AppendUint32(record, uint32(len(key)))
+		record = append(record, key...)
+		record = binary.BigEndian.AppendUint32(record, uint32(len(value)))
+		record = append(record, value...)
+		record = binary.BigEndian.AppendUint32(record, crc32.Checksum(record, snapshotTable))
+
+		if _, err := bw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadSnapshot adds the entries of a snapshot written by WriteSnapshot to
+// the cache, so that they end up in the same recency order as when the
+// snapshot was written. Entries that have expired since, or that exceed the
+// cache's budget, are skipped. The cache is left unchanged if the snapshot
+// is corrupt.
+func (lru *LRUCache[K, V]) ReadSnapshot(r io.Reader, keys Codec[K], values Codec[V]) error {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(snapshotMagic)+2+8+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return fmt.Errorf("%w: header: %v", ErrCorruptSnapshot, err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return fmt.Errorf("%w: bad magic", ErrCorruptSnapshot)
+	}
+	if crc32.Checksum(header[:len(header)-4], snapshotTable) != binary.BigEndian.Uint32(header[len(header)-4:]) {
+		return fmt.Errorf("%w: header checksum mismatch", ErrCorruptSnapshot)
+	}
+	if version := binary.BigEndian.Uint16(header[len(snapshotMagic):]); version != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSnapshotVersion, version)
+	}
+	count := binary.BigEndian.Uint64(header[len(snapshotMagic)+2:])
+
+	var entries []snapshotEntry[K, V]
+	for i := range count {
+		e, err := readSnapshotEntry(br, keys, values)
+		if err != nil {
+			return fmt.Errorf("%w: entry %d: %v", ErrCorruptSnapshot, i, err)
+		}
+		entries = append(entries, e)
+	}
+
+	lru.mu.Lock()
+	defer lru.unlock()
+
+	now := lru.clock.Now()
+	for _, e := range entries {
+		if !e.expiresAt.IsZero() && !now.Before(e.expiresAt) {
+			continue
+		}
+		if err := lru.put(e.key, e.value, e.expiresAt); err != nil && !errors.Is(err, ErrTooLarge) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readSnapshotEntry[K comparable, V any](r io.Reader, keys Codec[K], values Codec[V]) (snapshotEntry[K, V], error) {
+	var e snapshotEntry[K, V]
+	crc := crc32.New(snapshotTable)
+	tr := io.TeeReader(r, crc)
+
+	var expiresAt int64
+	if err := binary.Read(tr, binary.BigEndian, &expiresAt); err != nil {
+		return e, err
+	}
+
+	key, err := readSnapshotBytes(tr)
+	if err != nil {
+		return e, err
+	}
+	value, err := readSnapshotBytes(tr)
+	if err != nil {
+		return e, err
+	}
+
+	sum := crc.Sum32()
+	var stored uint32
+	if err := binary.Read(r, binary.BigEndian, &stored); err != nil {
+		return e, err
+	}
+	if sum != stored {
+		return e, errors.New("checksum mismatch")
+	}
+
+	if e.key, err = keys.Decode(key); err != nil {
+		return e, fmt.Errorf("decode key: %w", err)
+	}
+	if e.value, err = values.Decode(value); err != nil {
+		return e, fmt.Errorf("decode value: %w", err)
+	}
+	if expiresAt != 0 {
+		e.expiresAt = time.Unix(0, expiresAt)
+	}
+
+	return e, nil
+}
+
+// readSnapshotBytes reads a length-prefixed key or value. The length is not
+// trusted before the checksum has been verified, so only as much is
+// allocated as can actually be read.
+func readSnapshotBytes(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != int(n) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return data, nil
+}
+
+// SaveSnapshot writes a snapshot to the file at path. The file is replaced
+// atomically, so a crash while saving leaves the previous snapshot intact.
+func (lru *LRUCache[K, V]) SaveSnapshot(path string, keys Codec[K], values Codec[V]) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := lru.WriteSnapshot(tmp, keys, values); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshot reads a snapshot from the file at path into the cache.
+func (lru *LRUCache[K, V]) LoadSnapshot(path string, keys Codec[K], values Codec[V]) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return lru.ReadSnapshot(f, keys, values)
+}
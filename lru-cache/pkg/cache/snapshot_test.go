@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recencyOrder returns the keys from the most to the least recently used.
+func recencyOrder[K comparable, V any](lru *LRUCache[K, V]) []K {
+	var keys []K
+	for node := lru.head.next; node != lru.tail; node = node.next {
+		keys = append(keys, node.key)
+	}
+
+	return keys
+}
+
+func TestSnapshot(t *testing.T) {
+	codecs := func() (Codec[string], Codec[int]) {
+		return GobCodec[string]{}, GobCodec[int]{}
+	}
+
+	t.Run("should restore entries in the same recency order", func(t *testing.T) {
+		lru := NewLRUCache[string, int](4)
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+		lru.Put("c", 3)
+		lru.Get("a")
+
+		var buf bytes.Buffer
+		keys, values := codecs()
+		require.NoError(t, lru.WriteSnapshot(&buf, keys, values))
+
+		restored := NewLRUCache[string, int](4)
+		require.NoError(t, restored.ReadSnapshot(&buf, keys, values))
+
+		assert.Equal(t, []string{"a", "c", "b"}, recencyOrder(restored))
+		value, _ := restored.Get("b")
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("should keep expiry times and skip entries that expired since", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru := NewLRUCache(4, WithClock[string, int](clock))
+		lru.PutWithTTL("short", 1, time.Minute)
+		lru.PutWithTTL("long", 2, time.Hour)
+		lru.Put("forever", 3)
+
+		var buf bytes.Buffer
+		keys, values := codecs()
+		require.NoError(t, lru.WriteSnapshot(&buf, keys, values))
+
+		clock.Advance(time.Minute)
+		restored := NewLRUCache(4, WithClock[string, int](clock))
+		require.NoError(t, restored.ReadSnapshot(&buf, keys, values))
+
+		assert.Equal(t, []string{"forever", "long"}, recencyOrder(restored))
+
+		clock.Advance(time.Hour)
+		_, found := restored.Get("long")
+		assert.False(t, found)
+	})
+
+	t.Run("should keep the most recently used entries if the snapshot does not fit", func(t *testing.T) {
+		lru := NewLRUCache[string, int](3)
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+		lru.Put("c", 3)
+
+		var buf bytes.Buffer
+		keys, values := codecs()
+		require.NoError(t, lru.WriteSnapshot(&buf, keys, values))
+
+		restored := NewLRUCache[string, int](2)
+		require.NoError(t, restored.ReadSnapshot(&buf, keys, values))
+
+		assert.Equal(t, []string{"c", "b"}, recencyOrder(restored))
+	})
+
+	t.Run("should detect corruption and leave the cache unchanged", func(t *testing.T) {
+		lru := NewLRUCache[string, int](4)
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+
+		var buf bytes.Buffer
+		keys, values := codecs()
+		require.NoError(t, lru.WriteSnapshot(&buf, keys, values))
+
+		data := buf.Bytes()
+		data[len(data)-6] ^= 0xff
+
+		restored := NewLRUCache[string, int](4)
+		err := restored.ReadSnapshot(bytes.NewReader(data), keys, values)
+
+		assert.ErrorIs(t, err, ErrCorruptSnapshot)
+		assert.Equal(t, 0, restored.Len())
+
+		err = restored.ReadSnapshot(bytes.NewReader(data[:len(data)-1]), keys, values)
+		assert.ErrorIs(t, err, ErrCorruptSnapshot)
+	})
+
+	t.Run("should not trust corrupt lengths", func(t *testing.T) {
+		lru := NewLRUCache[string, int](4)
+		lru.Put("a", 1)
+
+		var buf bytes.Buffer
+		keys, values := codecs()
+		require.NoError(t, lru.WriteSnapshot(&buf, keys, values))
+
+		// The key length follows the header and the entry's expiry time.
+		data := buf.Bytes()
+		binary.BigEndian.PutUint32(data[len(snapshotMagic)+2+8+4+8:], 1<<29)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		err := NewLRUCache[string, int](4).ReadSnapshot(bytes.NewReader(data), keys, values)
+		runtime.ReadMemStats(&after)
+
+		assert.ErrorIs(t, err, ErrCorruptSnapshot)
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+	})
+
+	t.Run("should reject unknown versions", func(t *testing.T) {
+		header := []byte(snapshotMagic)
+		header = binary.BigEndian.AppendUint16(header, snapshotVersion+1)
+		header = binary.BigEndian.AppendUint64(header, 0)
+		header = binary.BigEndian.AppendUint32(header, crc32.Checksum(header, snapshotTable))
+
+		keys, values := codecs()
+		err := NewLRUCache[string, int](4).ReadSnapshot(bytes.NewReader(header), keys, values)
+
+		assert.ErrorIs(t, err, ErrUnsupportedSnapshotVersion)
+	})
+
+	t.Run("should save to and load from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+		lru := NewLRUCache[string, int](4)
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+
+		keys, values := codecs()
+		require.NoError(t, lru.SaveSnapshot(path, keys, values))
+
+		restored := NewLRUCache[string, int](4)
+		require.NoError(t, restored.LoadSnapshot(path, keys, values))
+
+		assert.Equal(t, []string{"b", "a"}, recencyOrder(restored))
+	})
+}
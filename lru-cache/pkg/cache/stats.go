@@ -0,0 +1,93 @@
+package cache
+
+import "sync/atomic"
+
+// counters are updated while the cache lock is held but read without it, so
+// that taking a snapshot never waits for the lock.
+type counters struct {
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+	insertions atomic.Uint64
+	updates    atomic.Uint64
+	evictions  [evictionReasons]atomic.Uint64
+}
+
+// Stats is a snapshot of the counters of a cache. The counters are read one
+// by one, so a snapshot taken while other goroutines use the cache is not
+// guaranteed to be consistent across counters.
+type Stats struct {
+	Hits       uint64
+	Misses     uint64
+	Insertions uint64
+	Updates    uint64
+	// Evictions counts the entries that left the cache, indexed by
+	// EvictionReason.
+	Evictions [evictionReasons]uint64
+}
+
+// Evicted returns the number of entries that left the cache for the given
+// reason.
+func (s Stats) Evicted(reason EvictionReason) uint64 {
+	return s.Evictions[reason]
+}
+
+// TotalEvictions returns the number of entries that left the cache for any
+// reason.
+func (s Stats) TotalEvictions() uint64 {
+	var total uint64
+	for _, n := range s.Evictions {
+		total += n
+	}
+
+	return total
+}
+
+// HitRatio returns the share of lookups that found a value, or 0 if there
+// were no lookups yet.
+func (s Stats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(lookups)
+}
+
+// MissRatio returns the share of lookups that found no value, or 0 if there
+// were no lookups yet.
+func (s Stats) MissRatio() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+
+	return float64(s.Misses) / float64(lookups)
+}
+
+func (s Stats) add(other Stats) Stats {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Insertions += other.Insertions
+	s.Updates += other.Updates
+	for i := range s.Evictions {
+		s.Evictions[i] += other.Evictions[i]
+	}
+
+	return s
+}
+
+// Stats returns a snapshot of the cache's counters without taking the cache
+// lock.
+func (lru *LRUCache[K, V]) Stats() Stats {
+	s := Stats{
+		Hits:       lru.stats.hits.Load(),
+		Misses:     lru.stats.misses.Load(),
+		Insertions: lru.stats.insertions.Load(),
+		Updates:    lru.stats.updates.Load(),
+	}
+	for i := range s.Evictions {
+		s.Evictions[i] = lru.stats.evictions[i].Load()
+	}
+
+	return s
+}
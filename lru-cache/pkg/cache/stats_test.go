@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("should count hits, misses, insertions, updates and evictions", func(t *testing.T) {
+		lru := NewLRUCache[string, int](1)
+		lru.Put("a", 1)
+		lru.Put("a", 2)
+		lru.Get("a")
+		lru.Get("b")
+		lru.Put("b", 3)
+		lru.Delete("b")
+
+		s := lru.Stats()
+
+		assert.Equal(t, uint64(1), s.Hits)
+		assert.Equal(t, uint64(1), s.Misses)
+		assert.Equal(t, uint64(2), s.Insertions)
+		assert.Equal(t, uint64(1), s.Updates)
+		assert.Equal(t, uint64(1), s.Evicted(EvictedCapacity))
+		assert.Equal(t, uint64(1), s.Evicted(EvictedReplaced))
+		assert.Equal(t, uint64(1), s.Evicted(EvictedDeleted))
+		assert.Equal(t, uint64(3), s.TotalEvictions())
+		assert.InDelta(t, 0.5, s.HitRatio(), 1e-9)
+		assert.InDelta(t, 0.5, s.MissRatio(), 1e-9)
+	})
+
+	t.Run("should report zero ratios before the first lookup", func(t *testing.T) {
+		s := NewLRUCache[string, int](1).Stats()
+
+		assert.Zero(t, s.HitRatio())
+		assert.Zero(t, s.MissRatio())
+	})
+
+	t.Run("should sum the statistics of all shards", func(t *testing.T) {
+		c := NewShardedLRUCache[int, int](4, 100)
+		for i := range 10 {
+			c.Put(i, i)
+			c.Get(i)
+		}
+
+		s := c.Stats()
+
+		assert.Equal(t, uint64(10), s.Insertions)
+		assert.Equal(t, uint64(10), s.Hits)
+	})
+}
+
+func TestPrometheus(t *testing.T) {
+	t.Run("should serve the statistics in the text exposition format", func(t *testing.T) {
+		lru := NewLRUCache[string, int](1)
+		lru.Put("a", 1)
+		lru.Put("b", 2)
+		lru.Get("b")
+
+		rec := httptest.NewRecorder()
+		PrometheusHandler("sessions", lru.Stats).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+
+		body := rec.Body.String()
+		assert.Contains(t, body, "# TYPE sessions_cache_hits_total counter\nsessions_cache_hits_total 1\n")
+		assert.Contains(t, body, "sessions_cache_insertions_total 2\n")
+		assert.Contains(t, body, `sessions_cache_evictions_total{reason="capacity"} 1`+"\n")
+		assert.Contains(t, body, `sessions_cache_evictions_total{reason="expired"} 0`+"\n")
+		assert.Contains(t, body, "sessions_cache_hit_ratio 1\n")
+	})
+}
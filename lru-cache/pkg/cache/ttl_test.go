@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTL(t *testing.T) {
+	t.Run("should expire entries lazily on Get", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru := NewLRUCache(2, WithClock[string, int](clock))
+		lru.PutWithTTL("a", 1, time.Minute)
+
+		clock.Advance(59 * time.Second)
+		_, found := lru.Get("a")
+		assert.True(t, found)
+
+		clock.Advance(time.Second)
+		_, found = lru.Get("a")
+		assert.False(t, found)
+		assert.Equal(t, 0, lru.Len())
+	})
+
+	t.Run("should keep entries without TTL forever", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru := NewLRUCache(2, WithClock[string, int](clock))
+		lru.Put("a", 1)
+		lru.PutWithTTL("b", 2, 0)
+
+		clock.Advance(24 * time.Hour)
+
+		_, found := lru.Get("a")
+		assert.True(t, found)
+		_, found = lru.Get("b")
+		assert.True(t, found)
+	})
+
+	t.Run("should expire entries at an absolute time", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru := NewLRUCache(2, WithClock[string, int](clock))
+		lru.PutWithExpiry("a", 1, clock.Now().Add(time.Minute))
+		lru.PutWithExpiry("b", 2, time.Time{})
+
+		clock.Advance(time.Minute)
+
+		_, found := lru.Get("a")
+		assert.False(t, found)
+		_, found = lru.Get("b")
+		assert.True(t, found)
+	})
+
+	t.Run("should reset the expiry when an entry is updated", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru := NewLRUCache(2, WithClock[string, int](clock))
+		lru.PutWithTTL("a", 1, time.Minute)
+
+		clock.Advance(30 * time.Second)
+		lru.Put("a", 2)
+		clock.Advance(time.Hour)
+
+		value, found := lru.Get("a")
+		assert.True(t, found)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("should remove all expired entries at once", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		lru := NewLRUCache(4, WithClock[string, int](clock))
+		lru.PutWithTTL("a", 1, time.Minute)
+		lru.PutWithTTL("b", 2, time.Hour)
+		lru.PutWithTTL("c", 3, time.Minute)
+		lru.Put("d", 4)
+
+		clock.Advance(time.Minute)
+
+		assert.Equal(t, 2, lru.RemoveExpired())
+		assert.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("should remove expired entries in the background", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		c := NewShardedLRUCache(2, 4, WithClock[string, int](clock))
+		c.PutWithTTL("a", 1, time.Minute)
+		c.PutWithTTL("b", 2, time.Minute)
+
+		stop := c.StartJanitor(time.Millisecond)
+		defer stop()
+
+		clock.Advance(time.Minute)
+
+		assert.Eventually(t, func() bool { return c.Len() == 0 }, time.Second, time.Millisecond)
+
+		stop()
+		stop()
+	})
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	// twoQueueRecentRatio is the share of the capacity reserved for entries
+	// that were only seen once.
+	twoQueueRecentRatio = 0.25
+	// twoQueueGhostRatio is the number of recently evicted keys remembered,
+	// relative to the capacity.
+	twoQueueGhostRatio = 0.5
+)
+
+type twoQueueEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	frequent bool
+}
+
+// TwoQueueCache implements the 2Q eviction policy. New entries enter a small
+// recent queue and are only promoted to the frequent queue when they are
+// used again, either while still in the recent queue or shortly after being
+// evicted from it. A single scan therefore only churns the recent queue and
+// leaves the frequently used entries alone.
+//
+// TwoQueueCache is safe for concurrent use.
+type TwoQueueCache[K comparable, V any] struct {
+	mu         sync.Mutex
+	capacity   int
+	recentSize int
+	ghostSize  int
+	// recent and frequent hold *twoQueueEntry values, ghost holds keys of
+	// entries recently evicted from recent. All lists keep the most
+	// recently used element at the front.
+	recent   *list.List
+	frequent *list.List
+	ghost    *list.List
+	items    map[K]*list.Element
+	ghosts   map[K]*list.Element
+}
+
+// NewTwoQueueCache panics if capacity is less than 1.
+func NewTwoQueueCache[K comparable, V any](capacity int) *TwoQueueCache[K, V] {
+	checkCapacity(capacity)
+
+	return &TwoQueueCache[K, V]{
+		capacity:   capacity,
+		recentSize: max(int(float64(capacity)*twoQueueRecentRatio), 1),
+		ghostSize:  max(int(float64(capacity)*twoQueueGhostRatio), 1),
+		recent:     list.New(),
+		frequent:   list.New(),
+		ghost:      list.New(),
+		items:      make(map[K]*list.Element),
+		ghosts:     make(map[K]*list.Element),
+	}
+}
+
+func (c *TwoQueueCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.items[key]
+	if !found {
+		var zero V
+
+		return zero, false
+	}
+
+	e := elem.Value.(*twoQueueEntry[K, V])
+	c.promote(elem)
+
+	return e.value, true
+}
+
+func (c *TwoQueueCache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.items[key]; found {
+		elem.Value.(*twoQueueEntry[K, V]).value = value
+		c.promote(elem)
+
+		return
+	}
+
+	e := &twoQueueEntry[K, V]{key: key, value: value}
+
+	// Keys seen again shortly after leaving the recent queue are used
+	// frequently enough to go straight to the frequent queue.
+	if ghost, found := c.ghosts[key]; found {
+		c.ghost.Remove(ghost)
+		delete(c.ghosts, key)
+		c.ensureSpace(true)
+		e.frequent = true
+		c.items[key] = c.frequent.PushFront(e)
+
+		return
+	}
+
+	c.ensureSpace(false)
+	c.items[key] = c.recent.PushFront(e)
+}
+
+func (c *TwoQueueCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ghost, found := c.ghosts[key]; found {
+		c.ghost.Remove(ghost)
+		delete(c.ghosts, key)
+	}
+
+	elem, found := c.items[key]
+	if !found {
+		return false
+	}
+
+	c.listOf(elem).Remove(elem)
+	delete(c.items, key)
+
+	return true
+}
+
+func (c *TwoQueueCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+// promote moves a used entry to the front of the frequent queue.
+func (c *TwoQueueCache[K, V]) promote(elem *list.Element) {
+	e := elem.Value.(*twoQueueEntry[K, V])
+	if e.frequent {
+		c.frequent.MoveToFront(elem)
+		return
+	}
+
+	c.recent.Remove(elem)
+	e.frequent = true
+	c.items[e.key] = c.frequent.PushFront(e)
+}
+
+// ensureSpace evicts an entry if the cache is full. It evicts from the
+// recent queue while that is over its share of the capacity, otherwise from
+// the frequent queue.
+func (c *TwoQueueCache[K, V]) ensureSpace(ghostHit bool) {
+	if len(c.items) < c.capacity || len(c.items) == 0 {
+		return
+	}
+
+	recentLen := c.recent.Len()
+	if recentLen > 0 && (recentLen > c.recentSize || (recentLen == c.recentSize && !ghostHit) || c.frequent.Len() == 0) {
+		e := c.recent.Remove(c.recent.Back()).(*twoQueueEntry[K, V])
+		delete(c.items, e.key)
+
+		c.ghosts[e.key] = c.ghost.PushFront(e.key)
+		if c.ghost.Len() > c.ghostSize {
+			delete(c.ghosts, c.ghost.Remove(c.ghost.Back()).(K))
+		}
+
+		return
+	}
+
+	e := c.frequent.Remove(c.frequent.Back()).(*twoQueueEntry[K, V])
+	delete(c.items, e.key)
+}
+
+func (c *TwoQueueCache[K, V]) listOf(elem *list.Element) *list.List {
+	if elem.Value.(*twoQueueEntry[K, V]).frequent {
+		return c.frequent
+	}
+
+	return c.recent
+}
@@ -0,0 +1,365 @@
+// Package httpcache provides an HTTP middleware that caches complete
+// responses in an LRUCache.
+//
+// Responses are cached per method, path, query and the request headers
+// named in their Vary header. How long a response stays fresh is taken from
+// its Cache-Control or Expires header. Cached responses carry an ETag so
+// that clients can revalidate them with If-None-Match, and every response
+// passing through the middleware is marked with an X-Cache header of HIT,
+// MISS or BYPASS.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache"
+)
+
+// StatusHeader is the response header that tells whether a response was
+// served from the cache.
+const StatusHeader = "X-Cache"
+
+const (
+	StatusHit    = "HIT"
+	StatusMiss   = "MISS"
+	StatusBypass = "BYPASS"
+)
+
+// cacheableStatus lists the status codes whose responses may be cached.
+var cacheableStatus = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusMovedPermanently,
+	http.StatusNotFound,
+	http.StatusGone,
+}
+
+// response is a cached response.
+type response struct {
+	status   int
+	header   http.Header
+	body     []byte
+	storedAt time.Time
+}
+
+func (r *response) cost() int64 {
+	cost := int64(len(r.body))
+	for name, values := range r.header {
+		cost += int64(len(name))
+		for _, v := range values {
+			cost += int64(len(v))
+		}
+	}
+
+	return cost
+}
+
+// Cache caches HTTP responses. It is safe for concurrent use.
+type Cache struct {
+	responses *cache.LRUCache[string, *response]
+	// vary remembers, per method, path and query, the request headers that
+	// select between the cached variants of a response.
+	vary       *cache.LRUCache[string, []string]
+	clock      cache.Clock
+	defaultTTL time.Duration
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithDefaultTTL caches responses that neither have a Cache-Control max-age
+// nor an Expires header for the given duration. By default such responses
+// are not cached.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.defaultTTL = ttl
+	}
+}
+
+// WithClock replaces the system clock, e.g. to control expiry in tests.
+func WithClock(clock cache.Clock) Option {
+	return func(c *Cache) {
+		c.clock = clock
+	}
+}
+
+// New creates a cache that holds at most maxBytes bytes of response headers
+// and bodies. It panics if maxBytes is not positive.
+func New(maxBytes int64, opts ...Option) *Cache {
+	c := &Cache{clock: cache.SystemClock()}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.responses = cache.NewLRUCacheWithCost(maxBytes, func(key string, r *response) int64 {
+		return int64(len(key)) + r.cost()
+	}, cache.WithClock[string, *response](c.clock))
+	c.vary = cache.NewLRUCache(1024, cache.WithClock[string, []string](c.clock))
+
+	return c
+}
+
+// Purge removes all cached responses.
+func (c *Cache) Purge() {
+	c.responses.Purge()
+	c.vary.Purge()
+}
+
+// Stats returns the statistics of the cached responses.
+func (c *Cache) Stats() cache.Stats {
+	return c.responses.Stats()
+}
+
+// Middleware serves cached responses for GET and HEAD requests and caches
+// the responses of next.
+func (c *Cache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bypass(r) {
+			w.Header().Set(StatusHeader, StatusBypass)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		base := baseKey(r)
+		names, _ := c.vary.Get(base)
+		if cached, found := c.responses.Get(variantKey(base, names, r)); found {
+			c.serve(w, r, cached, StatusHit)
+			return
+		}
+
+		rec := &recorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		resp := &response{
+			status:   rec.status,
+			header:   rec.header,
+			body:     rec.body.Bytes(),
+			storedAt: c.clock.Now(),
+		}
+		if resp.status == http.StatusOK && resp.header.Get("ETag") == "" {
+			resp.header.Set("ETag", etag(resp.body))
+		}
+
+		c.store(base, r, resp)
+		c.serve(w, r, resp, StatusMiss)
+	})
+}
+
+// store caches the response if its headers allow it.
+func (c *Cache) store(base string, r *http.Request, resp *response) {
+	if !slices.Contains(cacheableStatus, resp.status) || resp.header.Get("Set-Cookie") != "" {
+		return
+	}
+
+	ttl, cacheable := freshness(resp.header, resp.storedAt, c.defaultTTL)
+	if !cacheable {
+		return
+	}
+
+	names := varyHeaders(resp.header)
+	if slices.Contains(names, "*") {
+		return
+	}
+
+	c.vary.PutWithTTL(base, names, ttl)
+	// Responses too large for the cache are simply not cached.
+	c.responses.PutWithTTL(variantKey(base, names, r), resp, ttl)
+}
+
+// serve writes the response, or 304 Not Modified if the client already has
+// the current version.
+func (c *Cache) serve(w http.ResponseWriter, r *http.Request, resp *response, status string) {
+	header := w.Header()
+	for name, values := range resp.header {
+		header[name] = slices.Clone(values)
+	}
+	header.Set(StatusHeader, status)
+	if status == StatusHit {
+		age := c.clock.Now().Sub(resp.storedAt)
+		header.Set("Age", strconv.FormatInt(int64(age.Seconds()), 10))
+	}
+
+	if resp.status == http.StatusOK && etagMatches(r.Header.Get("If-None-Match"), header.Get("ETag")) {
+		header.Del("Content-Length")
+		header.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(resp.body)))
+	w.WriteHeader(resp.status)
+	if r.Method != http.MethodHead {
+		w.Write(resp.body)
+	}
+}
+
+// bypass tells whether the request must not be answered from the cache.
+func bypass(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return true
+	}
+
+	if r.Header.Get("Authorization") != "" {
+		return true
+	}
+
+	directives := cacheControl(r.Header)
+	_, noCache := directives["no-cache"]
+	_, noStore := directives["no-store"]
+
+	return noCache || noStore
+}
+
+// baseKey identifies a resource by method, path and query. The query is
+// normalized so that the order of its parameters does not matter.
+func baseKey(r *http.Request) string {
+	query := r.URL.Query()
+
+	return r.Method + " " + r.URL.EscapedPath() + "?" + query.Encode()
+}
+
+// variantKey extends the base key with the values of the given request
+// headers.
+func variantKey(base string, names []string, r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, name := range names {
+		b.WriteString("\n")
+		b.WriteString(name)
+		b.WriteString(": ")
+		b.WriteString(url.QueryEscape(strings.Join(r.Header.Values(name), ",")))
+	}
+
+	return b.String()
+}
+
+// varyHeaders returns the sorted, canonical header names listed in the
+// Vary header of a response.
+func varyHeaders(h http.Header) []string {
+	var names []string
+	for _, v := range h.Values("Vary") {
+		for name := range strings.SplitSeq(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+// freshness returns how long a response stays fresh and whether it may be
+// cached at all.
+func freshness(h http.Header, now time.Time, defaultTTL time.Duration) (time.Duration, bool) {
+	directives := cacheControl(h)
+	for _, d := range []string{"no-store", "no-cache", "private"} {
+		if _, found := directives[d]; found {
+			return 0, false
+		}
+	}
+
+	// s-maxage is meant for shared caches like this one and takes
+	// precedence over max-age.
+	for _, d := range []string{"s-maxage", "max-age"} {
+		if v, found := directives[d]; found {
+			seconds, err := strconv.Atoi(v)
+			if err != nil || seconds <= 0 {
+				return 0, false
+			}
+
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	if v := h.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return 0, false
+		}
+
+		if date, err := http.ParseTime(h.Get("Date")); err == nil {
+			now = date
+		}
+
+		ttl := expires.Sub(now)
+		return ttl, ttl > 0
+	}
+
+	return defaultTTL, defaultTTL > 0
+}
+
+// cacheControl parses the Cache-Control header into its directives.
+func cacheControl(h http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, v := range h.Values("Cache-Control") {
+		for directive := range strings.SplitSeq(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+			}
+		}
+	}
+
+	return directives
+}
+
+// etag derives a strong entity tag from the body.
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header matches the entity
+// tag, using the weak comparison required for If-None-Match.
+func etagMatches(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" || tag == "" {
+		return false
+	}
+
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recorder buffers the response of a handler.
+type recorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+
+	return r.body.Write(p)
+}
@@ -0,0 +1,254 @@
+package httpcache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler answers with the number of requests it has served, so
+// that tests can tell cached from fresh responses.
+type countingHandler struct {
+	mu     sync.Mutex
+	calls  int
+	header http.Header
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.calls++
+	calls := h.calls
+	h.mu.Unlock()
+
+	for name, values := range h.header {
+		w.Header()[name] = values
+	}
+	if h.status != 0 {
+		w.WriteHeader(h.status)
+	}
+	fmt.Fprintf(w, "response %d for %s", calls, r.Header.Get("Accept-Language"))
+}
+
+func get(t *testing.T, handler http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func maxAge(seconds int) http.Header {
+	return http.Header{"Cache-Control": {fmt.Sprintf("max-age=%d", seconds)}}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should serve fresh responses from the cache", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60)}
+		handler := New(1024).Middleware(next)
+
+		first := get(t, handler, "/hello", nil)
+		second := get(t, handler, "/hello", nil)
+
+		assert.Equal(t, StatusMiss, first.Header().Get(StatusHeader))
+		assert.Equal(t, StatusHit, second.Header().Get(StatusHeader))
+		assert.Equal(t, "response 1 for ", second.Body.String())
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("should key responses by method, path and normalized query", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60)}
+		handler := New(1024).Middleware(next)
+
+		get(t, handler, "/search?a=1&b=2", nil)
+
+		assert.Equal(t, StatusHit, get(t, handler, "/search?b=2&a=1", nil).Header().Get(StatusHeader))
+		assert.Equal(t, StatusMiss, get(t, handler, "/search?a=2", nil).Header().Get(StatusHeader))
+		assert.Equal(t, StatusMiss, get(t, handler, "/other?a=1&b=2", nil).Header().Get(StatusHeader))
+
+		head := httptest.NewRecorder()
+		handler.ServeHTTP(head, httptest.NewRequest(http.MethodHead, "/search?a=1&b=2", nil))
+		assert.Equal(t, StatusMiss, head.Header().Get(StatusHeader))
+		assert.Empty(t, head.Body.String())
+	})
+
+	t.Run("should cache a variant per value of the Vary headers", func(t *testing.T) {
+		next := &countingHandler{header: http.Header{
+			"Cache-Control": {"max-age=60"},
+			"Vary":          {"Accept-Language"},
+		}}
+		handler := New(1024).Middleware(next)
+
+		german := http.Header{"Accept-Language": {"de"}}
+		english := http.Header{"Accept-Language": {"en"}}
+
+		get(t, handler, "/hello", german)
+		get(t, handler, "/hello", english)
+
+		rec := get(t, handler, "/hello", german)
+		assert.Equal(t, StatusHit, rec.Header().Get(StatusHeader))
+		assert.Equal(t, "response 1 for de", rec.Body.String())
+
+		rec = get(t, handler, "/hello", english)
+		assert.Equal(t, StatusHit, rec.Header().Get(StatusHeader))
+		assert.Equal(t, "response 2 for en", rec.Body.String())
+	})
+
+	t.Run("should expire responses according to max-age", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		next := &countingHandler{header: maxAge(60)}
+		handler := New(1024, WithClock(clock)).Middleware(next)
+
+		get(t, handler, "/hello", nil)
+
+		clock.Advance(30 * time.Second)
+		rec := get(t, handler, "/hello", nil)
+		assert.Equal(t, StatusHit, rec.Header().Get(StatusHeader))
+		assert.Equal(t, "30", rec.Header().Get("Age"))
+
+		clock.Advance(30 * time.Second)
+		assert.Equal(t, StatusMiss, get(t, handler, "/hello", nil).Header().Get(StatusHeader))
+	})
+
+	t.Run("should expire responses according to Expires", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		next := &countingHandler{header: http.Header{
+			"Date":    {clock.Now().Format(http.TimeFormat)},
+			"Expires": {clock.Now().Add(time.Minute).Format(http.TimeFormat)},
+		}}
+		handler := New(1024, WithClock(clock)).Middleware(next)
+
+		get(t, handler, "/hello", nil)
+
+		clock.Advance(59 * time.Second)
+		assert.Equal(t, StatusHit, get(t, handler, "/hello", nil).Header().Get(StatusHeader))
+
+		clock.Advance(time.Second)
+		assert.Equal(t, StatusMiss, get(t, handler, "/hello", nil).Header().Get(StatusHeader))
+	})
+
+	t.Run("should not cache responses that forbid it", func(t *testing.T) {
+		for _, header := range []http.Header{
+			{"Cache-Control": {"no-store"}},
+			{"Cache-Control": {"private, max-age=60"}},
+			{"Cache-Control": {"no-cache"}},
+			{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"session=1"}},
+			{"Cache-Control": {"max-age=60"}, "Vary": {"*"}},
+			{"Expires": {"0"}},
+			{},
+		} {
+			next := &countingHandler{header: header}
+			handler := New(1024).Middleware(next)
+
+			get(t, handler, "/hello", nil)
+			assert.Equal(t, StatusMiss, get(t, handler, "/hello", nil).Header().Get(StatusHeader), header)
+		}
+	})
+
+	t.Run("should not cache error responses", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60), status: http.StatusInternalServerError}
+		handler := New(1024).Middleware(next)
+
+		get(t, handler, "/hello", nil)
+		rec := get(t, handler, "/hello", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, StatusMiss, rec.Header().Get(StatusHeader))
+	})
+
+	t.Run("should cache responses without freshness information for the default TTL", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		next := &countingHandler{}
+		handler := New(1024, WithClock(clock), WithDefaultTTL(time.Minute)).Middleware(next)
+
+		get(t, handler, "/hello", nil)
+		assert.Equal(t, StatusHit, get(t, handler, "/hello", nil).Header().Get(StatusHeader))
+
+		clock.Advance(time.Minute)
+		assert.Equal(t, StatusMiss, get(t, handler, "/hello", nil).Header().Get(StatusHeader))
+	})
+
+	t.Run("should bypass the cache for unsafe methods and uncacheable requests", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60)}
+		handler := New(1024).Middleware(next)
+
+		get(t, handler, "/hello", nil)
+
+		post := httptest.NewRecorder()
+		handler.ServeHTTP(post, httptest.NewRequest(http.MethodPost, "/hello", nil))
+		assert.Equal(t, StatusBypass, post.Header().Get(StatusHeader))
+
+		assert.Equal(t, StatusBypass, get(t, handler, "/hello", http.Header{"Cache-Control": {"no-cache"}}).Header().Get(StatusHeader))
+		assert.Equal(t, StatusBypass, get(t, handler, "/hello", http.Header{"Authorization": {"Bearer token"}}).Header().Get(StatusHeader))
+		assert.Equal(t, 4, next.calls)
+	})
+
+	t.Run("should answer matching If-None-Match requests with 304", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60)}
+		handler := New(1024).Middleware(next)
+
+		first := get(t, handler, "/hello", nil)
+		tag := first.Header().Get("ETag")
+		require.NotEmpty(t, tag)
+
+		for _, ifNoneMatch := range []string{tag, "W/" + tag, `"other", ` + tag, "*"} {
+			rec := get(t, handler, "/hello", http.Header{"If-None-Match": {ifNoneMatch}})
+
+			assert.Equal(t, http.StatusNotModified, rec.Code, ifNoneMatch)
+			assert.Empty(t, rec.Body.String())
+			assert.Equal(t, tag, rec.Header().Get("ETag"))
+		}
+
+		rec := get(t, handler, "/hello", http.Header{"If-None-Match": {`"other"`}})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "response 1 for ", rec.Body.String())
+	})
+
+	t.Run("should keep the ETag set by the handler", func(t *testing.T) {
+		next := &countingHandler{header: http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}}}
+		handler := New(1024).Middleware(next)
+
+		assert.Equal(t, `"v1"`, get(t, handler, "/hello", nil).Header().Get("ETag"))
+		assert.Equal(t, http.StatusNotModified, get(t, handler, "/hello", http.Header{"If-None-Match": {`"v1"`}}).Code)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("should evict the least recently used responses when full", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60)}
+		c := New(200)
+		handler := c.Middleware(next)
+
+		get(t, handler, "/a", nil)
+		get(t, handler, "/b", nil)
+		get(t, handler, "/a", nil)
+		get(t, handler, "/c", nil)
+
+		assert.Equal(t, StatusHit, get(t, handler, "/a", nil).Header().Get(StatusHeader))
+		assert.Equal(t, StatusMiss, get(t, handler, "/b", nil).Header().Get(StatusHeader))
+		assert.NotZero(t, c.Stats().TotalEvictions())
+	})
+
+	t.Run("should forget everything on Purge", func(t *testing.T) {
+		next := &countingHandler{header: maxAge(60)}
+		c := New(1024)
+		handler := c.Middleware(next)
+
+		get(t, handler, "/hello", nil)
+		c.Purge()
+
+		assert.Equal(t, StatusMiss, get(t, handler, "/hello", nil).Header().Get(StatusHeader))
+	})
+}
@@ -0,0 +1,594 @@
+// Package memcache serves an LRUCache over TCP using a subset of the
+// memcached text protocol: get, gets, set, add, replace, cas, delete,
+// incr, decr, flush_all, stats, version and quit.
+package memcache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache"
+)
+
+var (
+	// errObjectTooLarge closes connections that announce more data than the
+	// cache can hold.
+	errObjectTooLarge = errors.New("object too large for cache")
+	// errLineTooLong closes connections that send command lines longer than
+	// maxLineLength.
+	errLineTooLong = errors.New("line too long")
+)
+
+const (
+	Version = "1.0.0-lru"
+
+	maxKeyLength = 250
+	// maxLineLength bounds command lines, like memcached does, so that a
+	// client cannot make the server buffer without limit.
+	maxLineLength = 2048
+	// maxRelativeExptime is the largest exptime that is interpreted as
+	// seconds from now; larger values are Unix timestamps.
+	maxRelativeExptime = 60 * 60 * 24 * 30
+)
+
+// item is what the server stores per key.
+type item struct {
+	flags     uint32
+	data      []byte
+	cas       uint64
+	expiresAt time.Time
+}
+
+// Server is a memcached compatible server in front of an LRUCache whose
+// capacity is given in bytes of stored data.
+type Server struct {
+	cache *cache.LRUCache[string, item]
+
+	// mu serializes commands that read and then write an item, such as add
+	// or incr, so that they are atomic with respect to each other.
+	mu       sync.Mutex
+	casID    atomic.Uint64
+	started  time.Time
+	cmdGet   atomic.Uint64
+	cmdSet   atomic.Uint64
+	maxBytes int64
+
+	// closeMu guards what Close releases.
+	closeMu   sync.Mutex
+	listeners []net.Listener
+	conns     map[net.Conn]struct{}
+	serving   sync.WaitGroup
+	// flushes are the timers of delayed flush_all commands.
+	flushes map[*time.Timer]struct{}
+	closed  atomic.Bool
+}
+
+// NewServer creates a server that stores at most maxBytes bytes of keys and
+// data.
+func NewServer(maxBytes int64) *Server {
+	return &Server{
+		cache: cache.NewLRUCacheWithCost(maxBytes, func(key string, it item) int64 {
+			return int64(len(key) + len(it.data))
+		}),
+		started:  time.Now(),
+		maxBytes: maxBytes,
+		conns:    make(map[net.Conn]struct{}),
+		flushes:  make(map[*time.Timer]struct{}),
+	}
+}
+
+// ListenAndServe listens on the TCP address and serves connections until
+// Close is called.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener until Close is called, then
+// returns net.ErrClosed.
+func (s *Server) Serve(l net.Listener) error {
+	s.closeMu.Lock()
+	s.listeners = append(s.listeners, l)
+	s.closeMu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.closed.Load() {
+				return net.ErrClosed
+			}
+			return err
+		}
+
+		if !s.track(conn) {
+			conn.Close()
+			return net.ErrClosed
+		}
+
+		go func() {
+			defer s.untrack(conn)
+
+			if err := s.serveConn(conn); err != nil && !errors.Is(err, io.EOF) && !s.closed.Load() {
+				log.Printf("memcache: %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// track registers the connection so that Close can close it. It reports
+// false if the server is already closed.
+func (s *Server) track(conn net.Conn) bool {
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+
+	if s.closed.Load() {
+		return false
+	}
+
+	s.conns[conn] = struct{}{}
+	s.serving.Add(1)
+
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	conn.Close()
+
+	s.closeMu.Lock()
+	delete(s.conns, conn)
+	s.closeMu.Unlock()
+
+	s.serving.Done()
+}
+
+// Close stops accepting connections, cancels delayed flushes, closes the
+// open connections and waits until they are no longer served.
+func (s *Server) Close() error {
+	s.closeMu.Lock()
+	s.closed.Store(true)
+
+	var errs []error
+	for _, l := range s.listeners {
+		errs = append(errs, l.Close())
+	}
+	s.listeners = nil
+
+	for timer := range s.flushes {
+		timer.Stop()
+		delete(s.flushes, timer)
+	}
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.closeMu.Unlock()
+
+	s.serving.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) serveConn(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	for {
+		line, err := readLine(r)
+		if errors.Is(err, errLineTooLong) {
+			w.WriteString("CLIENT_ERROR line too long\r\n")
+			return w.Flush()
+		}
+		if err != nil {
+			return err
+		}
+
+		fields := strings.Fields(strings.TrimRight(line, "\r\n"))
+		if len(fields) == 0 {
+			w.WriteString("ERROR\r\n")
+		} else if fields[0] == "quit" {
+			return w.Flush()
+		} else if err := s.handle(fields, r, w); errors.Is(err, errObjectTooLarge) {
+			// Deliver the reply explaining why the connection is closed.
+			return w.Flush()
+		} else if err != nil {
+			return err
+		}
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// readLine reads a command line of at most maxLineLength bytes.
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > maxLineLength {
+			return "", errLineTooLong
+		}
+		line = append(line, chunk...)
+
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return string(line), err
+		}
+	}
+}
+
+// skipLine discards the rest of the current line without buffering it.
+func skipLine(r *bufio.Reader) error {
+	for {
+		_, err := r.ReadSlice('\n')
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return err
+		}
+	}
+}
+
+// handle runs a single command. It only returns an error if the connection
+// cannot be used any more.
+func (s *Server) handle(fields []string, r *bufio.Reader, w *bufio.Writer) error {
+	switch cmd, args := fields[0], fields[1:]; cmd {
+	case "get", "gets":
+		s.get(args, cmd == "gets", w)
+	case "set", "add", "replace", "cas":
+		return s.store(cmd, args, r, w)
+	case "delete":
+		s.delete(args, w)
+	case "incr", "decr":
+		s.incr(args, cmd == "incr", w)
+	case "flush_all":
+		s.flushAll(args, w)
+	case "stats":
+		s.stats(w)
+	case "version":
+		w.WriteString("VERSION " + Version + "\r\n")
+	default:
+		w.WriteString("ERROR\r\n")
+	}
+
+	return nil
+}
+
+func (s *Server) get(keys []string, withCAS bool, w *bufio.Writer) {
+	if len(keys) == 0 {
+		w.WriteString("ERROR\r\n")
+		return
+	}
+
+	for _, key := range keys {
+		s.cmdGet.Add(1)
+
+		it, found := s.cache.Get(key)
+		if !found {
+			continue
+		}
+
+		if withCAS {
+			fmt.Fprintf(w, "VALUE %s %d %d %d\r\n", key, it.flags, len(it.data), it.cas)
+		} else {
+			fmt.Fprintf(w, "VALUE %s %d %d\r\n", key, it.flags, len(it.data))
+		}
+		w.Write(it.data)
+		w.WriteString("\r\n")
+	}
+
+	w.WriteString("END\r\n")
+}
+
+// store handles set, add, replace and cas:
+//
+//	<cmd> <key> <flags> <exptime> <bytes> [<cas unique>] [noreply]
+func (s *Server) store(cmd string, args []string, r *bufio.Reader, w *bufio.Writer) error {
+	wantArgs := 4
+	if cmd == "cas" {
+		wantArgs = 5
+	}
+
+	noreply := len(args) == wantArgs+1 && args[wantArgs] == "noreply"
+	if len(args) != wantArgs && !noreply {
+		w.WriteString("ERROR\r\n")
+		return nil
+	}
+
+	key := args[0]
+	flags, flagsErr := strconv.ParseUint(args[1], 10, 32)
+	exptime, exptimeErr := strconv.ParseInt(args[2], 10, 64)
+	size, sizeErr := strconv.Atoi(args[3])
+	var casUnique uint64
+	var casErr error
+	if cmd == "cas" {
+		casUnique, casErr = strconv.ParseUint(args[4], 10, 64)
+	}
+
+	if sizeErr != nil || size < 0 {
+		// Without a valid length the data block cannot be skipped.
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return nil
+	}
+	if int64(size) > s.maxBytes {
+		// The length is checked before anything is allocated. Skipping the
+		// data block could take forever, so the connection is closed.
+		w.WriteString("SERVER_ERROR object too large for cache\r\n")
+		return errObjectTooLarge
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	if string(data[size:]) != "\r\n" {
+		// Skip the rest of the oversized data block so that it is not
+		// mistaken for the next command.
+		if data[len(data)-1] != '\n' {
+			if err := skipLine(r); err != nil {
+				return err
+			}
+		}
+		w.WriteString("CLIENT_ERROR bad data chunk\r\n")
+		return nil
+	}
+	data = data[:size]
+
+	if !validKey(key) || flagsErr != nil || exptimeErr != nil || casErr != nil {
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return nil
+	}
+
+	s.cmdSet.Add(1)
+	reply := s.storeItem(cmd, key, item{flags: uint32(flags), data: data, expiresAt: expiry(exptime)}, casUnique)
+
+	if !noreply {
+		w.WriteString(reply + "\r\n")
+	}
+
+	return nil
+}
+
+func (s *Server) storeItem(cmd, key string, it item, casUnique uint64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, found := s.cache.Peek(key)
+
+	switch {
+	case cmd == "add" && found, cmd == "replace" && !found:
+		return "NOT_STORED"
+	case cmd == "cas" && !found:
+		return "NOT_FOUND"
+	case cmd == "cas" && existing.cas != casUnique:
+		return "EXISTS"
+	}
+
+	if err := s.put(key, it); err != nil {
+		return "SERVER_ERROR object too large for cache"
+	}
+
+	return "STORED"
+}
+
+// put stores the item with a new CAS value. Items whose expiry time has
+// already passed are removed instead.
+func (s *Server) put(key string, it item) error {
+	it.cas = s.casID.Add(1)
+
+	if it.expiresAt.IsZero() {
+		return s.cache.TryPut(key, it)
+	}
+
+	ttl := time.Until(it.expiresAt)
+	if ttl <= 0 {
+		s.cache.Delete(key)
+		return nil
+	}
+
+	return s.cache.TryPutWithTTL(key, it, ttl)
+}
+
+func (s *Server) delete(args []string, w *bufio.Writer) {
+	noreply := len(args) == 2 && args[1] == "noreply"
+	if len(args) != 1 && !noreply {
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return
+	}
+
+	// Deleting under the lock keeps it from falling between the read and the
+	// write of an incr or cas, which would bring the item back.
+	s.mu.Lock()
+	deleted := s.cache.Delete(args[0])
+	s.mu.Unlock()
+
+	reply := "NOT_FOUND"
+	if deleted {
+		reply = "DELETED"
+	}
+
+	if !noreply {
+		w.WriteString(reply + "\r\n")
+	}
+}
+
+func (s *Server) incr(args []string, increment bool, w *bufio.Writer) {
+	noreply := len(args) == 3 && args[2] == "noreply"
+	if len(args) != 2 && !noreply {
+		w.WriteString("ERROR\r\n")
+		return
+	}
+
+	delta, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		w.WriteString("CLIENT_ERROR invalid numeric delta argument\r\n")
+		return
+	}
+
+	reply := s.incrItem(args[0], delta, increment)
+	if !noreply {
+		w.WriteString(reply + "\r\n")
+	}
+}
+
+func (s *Server) incrItem(key string, delta uint64, increment bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, found := s.cache.Peek(key)
+	if !found {
+		return "NOT_FOUND"
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(string(it.data)), 10, 64)
+	if err != nil {
+		return "CLIENT_ERROR cannot increment or decrement non-numeric value"
+	}
+
+	if increment {
+		// Increments wrap around at 64 bits, like memcached does.
+		value += delta
+	} else if delta > value {
+		// Decrements stop at zero.
+		value = 0
+	} else {
+		value -= delta
+	}
+
+	it.data = []byte(strconv.FormatUint(value, 10))
+	if err := s.put(key, it); err != nil {
+		return "SERVER_ERROR object too large for cache"
+	}
+
+	return string(it.data)
+}
+
+func (s *Server) flushAll(args []string, w *bufio.Writer) {
+	noreply := len(args) > 0 && args[len(args)-1] == "noreply"
+	if noreply {
+		args = args[:len(args)-1]
+	}
+
+	var delay int64
+	if len(args) > 0 {
+		var err error
+		if delay, err = strconv.ParseInt(args[0], 10, 64); err != nil || len(args) > 1 {
+			w.WriteString("CLIENT_ERROR bad command line format\r\n")
+			return
+		}
+	}
+
+	if delay > 0 {
+		s.flushLater(time.Duration(delay) * time.Second)
+	} else {
+		s.mu.Lock()
+		s.cache.Purge()
+		s.mu.Unlock()
+	}
+
+	if !noreply {
+		w.WriteString("OK\r\n")
+	}
+}
+
+// flushLater removes the items stored so far once delay has passed. Items
+// stored in the meantime are kept.
+func (s *Server) flushLater(delay time.Duration) {
+	// CAS values only grow, so they tell which items were stored before.
+	last := s.casID.Load()
+
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+
+	if s.closed.Load() {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		s.closeMu.Lock()
+		delete(s.flushes, timer)
+		s.closeMu.Unlock()
+
+		s.flush(last)
+	})
+	s.flushes[timer] = struct{}{}
+}
+
+// flush removes the items whose CAS value is at most last.
+func (s *Server) flush(last uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, it := range s.cache.All() {
+		if it.cas <= last {
+			s.cache.Delete(key)
+		}
+	}
+}
+
+func (s *Server) stats(w *bufio.Writer) {
+	st := s.cache.Stats()
+	now := time.Now()
+
+	stat := func(name string, value any) {
+		fmt.Fprintf(w, "STAT %s %v\r\n", name, value)
+	}
+
+	stat("pid", os.Getpid())
+	stat("uptime", int64(now.Sub(s.started).Seconds()))
+	stat("time", now.Unix())
+	stat("version", Version)
+	stat("cmd_get", s.cmdGet.Load())
+	stat("cmd_set", s.cmdSet.Load())
+	stat("get_hits", st.Hits)
+	stat("get_misses", st.Misses)
+	stat("curr_items", s.cache.Len())
+	stat("bytes", s.cache.Cost())
+	stat("limit_maxbytes", s.maxBytes)
+	stat("evictions", st.Evicted(cache.EvictedCapacity))
+	// Expired items are removed when a get finds them, so they were
+	// fetched and memcached's expired_unfetched would be wrong.
+	stat("expired", st.Evicted(cache.EvictedExpired))
+	w.WriteString("END\r\n")
+}
+
+// expiry converts a memcached exptime into an absolute time. Zero means the
+// item never expires, negative values mean it has already expired.
+func expiry(exptime int64) time.Time {
+	switch {
+	case exptime == 0:
+		return time.Time{}
+	case exptime < 0:
+		return time.Unix(1, 0)
+	case exptime <= maxRelativeExptime:
+		return time.Now().Add(time.Duration(exptime) * time.Second)
+	default:
+		return time.Unix(exptime, 0)
+	}
+}
+
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > maxKeyLength {
+		return false
+	}
+
+	for _, c := range []byte(key) {
+		if c <= ' ' || c == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
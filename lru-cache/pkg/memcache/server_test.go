@@ -0,0 +1,339 @@
+package memcache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// client speaks the text protocol to a server over loopback.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// startServer serves a new server on a random loopback port and returns a
+// connected client.
+func startServer(t *testing.T, maxBytes int64) (*Server, *client) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := NewServer(maxBytes)
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+
+	return s, dial(t, l.Addr().String())
+}
+
+func dial(t *testing.T, addr string) *client {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// do sends the request and reads lines until the response is complete,
+// which is after a line equal to end or after a single line if end is "".
+func (c *client) do(request, end string) []string {
+	c.t.Helper()
+
+	_, err := fmt.Fprint(c.conn, request)
+	require.NoError(c.t, err)
+
+	var lines []string
+	for {
+		require.NoError(c.t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		line, err := c.r.ReadString('\n')
+		require.NoError(c.t, err)
+
+		line = strings.TrimSuffix(line, "\r\n")
+		lines = append(lines, line)
+		if end == "" || line == end {
+			return lines
+		}
+	}
+}
+
+func (c *client) line(request string) string {
+	c.t.Helper()
+
+	return c.do(request, "")[0]
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should store and retrieve items", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		assert.Equal(t, "STORED", c.line("set greeting 42 0 5\r\nhello\r\n"))
+		assert.Equal(t, "STORED", c.line("set empty 0 0 0\r\n\r\n"))
+
+		assert.Equal(t, []string{
+			"VALUE greeting 42 5", "hello",
+			"VALUE empty 0 0", "",
+			"END",
+		}, c.do("get greeting missing empty\r\n", "END"))
+	})
+
+	t.Run("should keep binary data intact", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		assert.Equal(t, "STORED", c.line("set k 0 0 4\r\na\r\nb\r\n"))
+		assert.Equal(t, []string{"VALUE k 0 4", "a", "b", "END"}, c.do("get k\r\n", "END"))
+	})
+
+	t.Run("should only add missing and replace existing items", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		assert.Equal(t, "NOT_STORED", c.line("replace k 0 0 1\r\na\r\n"))
+		assert.Equal(t, "STORED", c.line("add k 0 0 1\r\nb\r\n"))
+		assert.Equal(t, "NOT_STORED", c.line("add k 0 0 1\r\nc\r\n"))
+		assert.Equal(t, "STORED", c.line("replace k 0 0 1\r\nd\r\n"))
+
+		assert.Equal(t, []string{"VALUE k 0 1", "d", "END"}, c.do("get k\r\n", "END"))
+	})
+
+	t.Run("should compare and swap using the value returned by gets", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		c.line("set k 0 0 1\r\na\r\n")
+		resp := c.do("gets k\r\n", "END")
+		require.Len(t, resp, 3)
+
+		var flags, size int
+		var cas uint64
+		_, err := fmt.Sscanf(resp[0], "VALUE k %d %d %d", &flags, &size, &cas)
+		require.NoError(t, err)
+
+		assert.Equal(t, "EXISTS", c.line(fmt.Sprintf("cas k 0 0 1 %d\r\nb\r\n", cas+1)))
+		assert.Equal(t, "STORED", c.line(fmt.Sprintf("cas k 0 0 1 %d\r\nc\r\n", cas)))
+		assert.Equal(t, "EXISTS", c.line(fmt.Sprintf("cas k 0 0 1 %d\r\nd\r\n", cas)))
+		assert.Equal(t, "NOT_FOUND", c.line("cas missing 0 0 1 1\r\ne\r\n"))
+
+		assert.Equal(t, []string{"VALUE k 0 1", "c", "END"}, c.do("get k\r\n", "END"))
+	})
+
+	t.Run("should delete items", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		c.line("set k 0 0 1\r\na\r\n")
+
+		assert.Equal(t, "DELETED", c.line("delete k\r\n"))
+		assert.Equal(t, "NOT_FOUND", c.line("delete k\r\n"))
+		assert.Equal(t, []string{"END"}, c.do("get k\r\n", "END"))
+	})
+
+	t.Run("should increment and decrement numeric values", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		c.line("set n 0 0 2\r\n10\r\n")
+		c.line("set s 0 0 3\r\nabc\r\n")
+
+		assert.Equal(t, "15", c.line("incr n 5\r\n"))
+		assert.Equal(t, "3", c.line("decr n 12\r\n"))
+		assert.Equal(t, "0", c.line("decr n 100\r\n"))
+		assert.Equal(t, "NOT_FOUND", c.line("incr missing 1\r\n"))
+		assert.Equal(t, "CLIENT_ERROR cannot increment or decrement non-numeric value", c.line("incr s 1\r\n"))
+		assert.Equal(t, "CLIENT_ERROR invalid numeric delta argument", c.line("incr n x\r\n"))
+
+		c.line("set max 0 0 20\r\n18446744073709551615\r\n")
+		assert.Equal(t, "1", c.line("incr max 2\r\n"))
+	})
+
+	t.Run("should expire items", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		assert.Equal(t, "STORED", c.line("set gone 0 -1 1\r\na\r\n"))
+		assert.Equal(t, "STORED", c.line(fmt.Sprintf("set past 0 %d 1\r\nb\r\n", time.Now().Add(-time.Hour).Unix())))
+		assert.Equal(t, "STORED", c.line("set soon 0 1 1\r\nc\r\n"))
+
+		assert.Equal(t, []string{"VALUE soon 0 1", "c", "END"}, c.do("get gone past soon\r\n", "END"))
+
+		assert.Eventually(t, func() bool {
+			return len(c.do("get soon\r\n", "END")) == 1
+		}, 3*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("should flush all items", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		c.line("set a 0 0 1\r\na\r\n")
+		c.line("set b 0 0 1\r\nb\r\n")
+
+		assert.Equal(t, "OK", c.line("flush_all\r\n"))
+		assert.Equal(t, []string{"END"}, c.do("get a b\r\n", "END"))
+	})
+
+	t.Run("should only flush items stored before a delayed flush_all", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		c.line("set a 0 0 1\r\na\r\n")
+		assert.Equal(t, "OK", c.line("flush_all 1\r\n"))
+		c.line("set b 0 0 1\r\nb\r\n")
+
+		assert.Equal(t, []string{"VALUE a 0 1", "a", "VALUE b 0 1", "b", "END"}, c.do("get a b\r\n", "END"))
+		assert.Eventually(t, func() bool {
+			return len(c.do("get a\r\n", "END")) == 1
+		}, 3*time.Second, 50*time.Millisecond)
+		assert.Equal(t, []string{"VALUE b 0 1", "b", "END"}, c.do("get b\r\n", "END"))
+	})
+
+	t.Run("should cancel delayed flushes on Close", func(t *testing.T) {
+		s, c := startServer(t, 1024)
+
+		c.line("set a 0 0 1\r\na\r\n")
+		assert.Equal(t, "OK", c.line("flush_all 1\r\n"))
+		require.NoError(t, s.Close())
+
+		time.Sleep(1500 * time.Millisecond)
+		assert.True(t, s.cache.Contains("a"))
+	})
+
+	t.Run("should not reply to noreply commands", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		reply := c.line("set k 0 0 1 noreply\r\na\r\n" +
+			"add k 0 0 1 noreply\r\nb\r\n" +
+			"incr missing 1 noreply\r\n" +
+			"delete missing noreply\r\n" +
+			"version\r\n")
+
+		assert.Equal(t, "VERSION "+Version, reply)
+		assert.Equal(t, []string{"VALUE k 0 1", "a", "END"}, c.do("get k\r\n", "END"))
+	})
+
+	t.Run("should evict the least recently used items when full", func(t *testing.T) {
+		s, c := startServer(t, 10)
+
+		c.line("set a 0 0 4\r\naaaa\r\n")
+		c.line("set b 0 0 4\r\nbbbb\r\n")
+		c.do("get a\r\n", "END")
+		c.line("set c 0 0 4\r\ncccc\r\n")
+
+		assert.Equal(t, []string{"VALUE a 0 4", "aaaa", "VALUE c 0 4", "cccc", "END"}, c.do("get a b c\r\n", "END"))
+		assert.Equal(t, "SERVER_ERROR object too large for cache", c.line("set big 0 0 20\r\n01234567890123456789\r\n"))
+		assert.Equal(t, uint64(1), s.cache.Stats().Evicted(cache.EvictedCapacity))
+	})
+
+	t.Run("should report statistics", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		c.line("set k 0 0 2\r\nhi\r\n")
+		c.do("get k missing\r\n", "END")
+
+		stats := make(map[string]string)
+		for _, line := range c.do("stats\r\n", "END") {
+			var name, value string
+			if _, err := fmt.Sscanf(line, "STAT %s %s", &name, &value); err == nil {
+				stats[name] = value
+			}
+		}
+
+		assert.Equal(t, "2", stats["cmd_get"])
+		assert.Equal(t, "1", stats["cmd_set"])
+		assert.Equal(t, "1", stats["get_hits"])
+		assert.Equal(t, "1", stats["get_misses"])
+		assert.Equal(t, "1", stats["curr_items"])
+		assert.Equal(t, "3", stats["bytes"])
+		assert.Equal(t, "1024", stats["limit_maxbytes"])
+		assert.Equal(t, "0", stats["expired"])
+	})
+
+	t.Run("should reject malformed commands", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		assert.Equal(t, "ERROR", c.line("bogus\r\n"))
+		assert.Equal(t, "ERROR", c.line("set k 0 0\r\n"))
+		assert.Equal(t, "CLIENT_ERROR bad command line format", c.line("set k x 0 1\r\na\r\n"))
+		assert.Equal(t, "CLIENT_ERROR bad data chunk", c.line("set k 0 0 1\r\nab\r\n"))
+		assert.Equal(t, "CLIENT_ERROR bad command line format", c.line(fmt.Sprintf("set %s 0 0 1\r\na\r\n", strings.Repeat("k", 251))))
+		assert.Equal(t, "VERSION "+Version, c.line("version\r\n"))
+	})
+
+	t.Run("should close the connection for items larger than the cache", func(t *testing.T) {
+		for _, size := range []string{"2000", "9223372036854775807"} {
+			_, c := startServer(t, 1024)
+
+			assert.Equal(t, "SERVER_ERROR object too large for cache", c.line("set k 0 0 "+size+"\r\n"))
+
+			require.NoError(t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			_, err := c.r.ReadString('\n')
+			assert.ErrorIs(t, err, io.EOF)
+
+			other := dial(t, c.conn.RemoteAddr().String())
+			assert.Equal(t, "STORED", other.line("set k 0 0 1\r\na\r\n"))
+		}
+	})
+
+	t.Run("should close the connection for overlong command lines", func(t *testing.T) {
+		_, c := startServer(t, 1024)
+
+		assert.Equal(t, "CLIENT_ERROR line too long", c.line("get "+strings.Repeat("k", 3000)+"\r\n"))
+
+		require.NoError(t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, err := c.r.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("should close open connections on Close", func(t *testing.T) {
+		s, c := startServer(t, 1024)
+		assert.Equal(t, "VERSION "+Version, c.line("version\r\n"))
+
+		require.NoError(t, s.Close())
+
+		require.NoError(t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, err := c.r.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("should serve many clients concurrently", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		s := NewServer(1 << 20)
+		go s.Serve(l)
+		t.Cleanup(func() { s.Close() })
+
+		c := dial(t, l.Addr().String())
+		c.line("set counter 0 0 1\r\n0\r\n")
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				conn, err := net.Dial("tcp", l.Addr().String())
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer conn.Close()
+
+				r := bufio.NewReader(conn)
+				for range 50 {
+					fmt.Fprint(conn, "incr counter 1\r\n")
+					if _, err := r.ReadString('\n'); !assert.NoError(t, err) {
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, []string{"VALUE counter 0 3", "400", "END"}, c.do("get counter\r\n", "END"))
+	})
+}
@@ -0,0 +1,505 @@
+package tiered
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Segment file layout: a sequence of records, all integers big endian:
+//
+//	crc32 of the rest of the record | kind byte | expiresAt int64 (Unix nanoseconds, 0 = never) | key length uint32 | value length uint32 | key | value
+//
+// Records are only ever appended. A put record makes its value the current
+// one for the key, a delete record removes the key. Replaying the segments
+// in order of their IDs therefore rebuilds the index.
+const (
+	recordHeaderSize = 4 + 1 + 8 + 4 + 4
+	segmentSuffix    = ".seg"
+
+	recordPut    byte = 0
+	recordDelete byte = 1
+
+	// DefaultMaxSegmentSize is the size at which a new segment is started.
+	DefaultMaxSegmentSize = 64 << 20
+)
+
+var ErrCorruptSegment = errors.New("corrupt segment")
+
+var segmentTable = crc32.MakeTable(crc32.Castagnoli)
+
+// location tells where the current record of a key is stored.
+type location struct {
+	segment   int
+	offset    int64
+	size      int64
+	expiresAt time.Time
+}
+
+type segment struct {
+	id   int
+	file *os.File
+	size int64
+}
+
+// SegmentStore is a key-value store on disk. Records are appended to
+// segment files, and an in-memory index points to the current record of
+// each key. Space taken up by overwritten and deleted records is reclaimed
+// by compaction, which rewrites the live records into new segments. It is
+// safe for concurrent use.
+type SegmentStore struct {
+	mu             sync.Mutex
+	dir            string
+	maxSegmentSize int64
+	segments       []*segment
+	index          map[string]location
+	// live and dead count the bytes of current and of superseded records.
+	live, dead int64
+}
+
+// OpenSegmentStore opens the store in dir, creating the directory if needed,
+// and rebuilds the index from its segments. A record that was only partly
+// written to the newest segment, e.g. because of a crash, is truncated.
+// Segments grow to about maxSegmentSize bytes; zero means
+// DefaultMaxSegmentSize.
+func OpenSegmentStore(dir string, maxSegmentSize int64) (*SegmentStore, error) {
+	if maxSegmentSize <= 0 {
+		maxSegmentSize = DefaultMaxSegmentSize
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	ids, err := segmentIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SegmentStore{
+		dir:            dir,
+		maxSegmentSize: maxSegmentSize,
+		index:          make(map[string]location),
+	}
+
+	for i, id := range ids {
+		if err := s.openSegment(id, i == len(ids)-1); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	if len(s.segments) == 0 {
+		if err := s.newSegment(1); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func segmentIDs(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, e := range entries {
+		name, found := strings.CutSuffix(e.Name(), segmentSuffix)
+		if !found || e.IsDir() {
+			continue
+		}
+		if id, err := strconv.Atoi(name); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+func (s *SegmentStore) segmentPath(id int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%06d%s", id, segmentSuffix))
+}
+
+// openSegment replays the records of a segment into the index. A torn
+// record at the end of the last segment is cut off, anywhere else it is an
+// error.
+func (s *SegmentStore) openSegment(id int, last bool) error {
+	f, err := os.OpenFile(s.segmentPath(id), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	seg := &segment{id: id, file: f}
+	s.segments = append(s.segments, seg)
+
+	r := bufio.NewReader(f)
+	for {
+		kind, expiresAt, key, _, size, err := readRecord(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if !last {
+				return fmt.Errorf("segment %d at offset %d: %w", id, seg.size, err)
+			}
+
+			return f.Truncate(seg.size)
+		}
+
+		if kind == recordPut {
+			s.setLocation(string(key), location{segment: id, offset: seg.size, size: size, expiresAt: expiresAt})
+		} else {
+			s.removeLocation(string(key))
+			s.dead += size
+		}
+		seg.size += size
+	}
+}
+
+func (s *SegmentStore) newSegment(id int) error {
+	f, err := os.OpenFile(s.segmentPath(id), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	s.segments = append(s.segments, &segment{id: id, file: f})
+
+	return nil
+}
+
+func (s *SegmentStore) active() *segment {
+	return s.segments[len(s.segments)-1]
+}
+
+// segmentByID finds a segment in a list sorted by ID.
+func segmentByID(segments []*segment, id int) *segment {
+	i, _ := slices.BinarySearchFunc(segments, id, func(seg *segment, id int) int {
+		return seg.id - id
+	})
+
+	return segments[i]
+}
+
+func (s *SegmentStore) setLocation(key string, loc location) {
+	s.removeLocation(key)
+	s.index[key] = loc
+	s.live += loc.size
+}
+
+func (s *SegmentStore) removeLocation(key string) bool {
+	old, found := s.index[key]
+	if found {
+		delete(s.index, key)
+		s.live -= old.size
+		s.dead += old.size
+	}
+
+	return found
+}
+
+// Get returns the value stored for the key and its expiry time. A zero
+// expiry time means the value never expires. The store does not check
+// expiry times itself.
+func (s *SegmentStore) Get(key []byte) (value []byte, expiresAt time.Time, found bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loc, found := s.index[string(key)]
+	if !found {
+		return nil, time.Time{}, false, nil
+	}
+
+	buf := make([]byte, loc.size)
+	if _, err := segmentByID(s.segments, loc.segment).file.ReadAt(buf, loc.offset); err != nil {
+		return nil, time.Time{}, false, err
+	}
+
+	_, expiresAt, _, value, _, err = readRecord(bytes.NewReader(buf))
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("segment %d at offset %d: %w", loc.segment, loc.offset, err)
+	}
+
+	return value, expiresAt, true, nil
+}
+
+// Put stores the value for the key.
+func (s *SegmentStore) Put(key, value []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loc, err := s.append(recordPut, key, value, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	s.setLocation(string(key), loc)
+
+	return s.maybeCompact()
+}
+
+// Delete removes the key and reports whether it was present.
+func (s *SegmentStore) Delete(key []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.index[string(key)]; !found {
+		return false, nil
+	}
+
+	loc, err := s.append(recordDelete, key, nil, time.Time{})
+	if err != nil {
+		return false, err
+	}
+
+	s.removeLocation(string(key))
+	s.dead += loc.size
+
+	return true, s.maybeCompact()
+}
+
+// Len returns the number of keys in the store, including those whose
+// values have expired but have not been removed yet.
+func (s *SegmentStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.index)
+}
+
+// Size returns the number of bytes taken up by the current records and by
+// superseded records that compaction has not reclaimed yet.
+func (s *SegmentStore) Size() (live, dead int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.live, s.dead
+}
+
+// append writes a record to the active segment, starting a new segment if
+// the active one is full.
+func (s *SegmentStore) append(kind byte, key, value []byte, expiresAt time.Time) (location, error) {
+	seg := s.active()
+	if seg.size > 0 && seg.size >= s.maxSegmentSize {
+		if err := s.newSegment(seg.id + 1); err != nil {
+			return location{}, err
+		}
+		seg = s.active()
+	}
+
+	record := encodeRecord(kind, key, value, expiresAt)
+	if _, err := seg.file.WriteAt(record, seg.size); err != nil {
+		return location{}, err
+	}
+
+	loc := location{segment: seg.id, offset: seg.size, size: int64(len(record)), expiresAt: expiresAt}
+	seg.size += loc.size
+
+	return loc, nil
+}
+
+// maybeCompact compacts the store once superseded records take up more
+// space than current ones and at least a full segment.
+func (s *SegmentStore) maybeCompact() error {
+	if s.dead < s.maxSegmentSize || s.dead < s.live {
+		return nil
+	}
+
+	return s.compact(time.Time{})
+}
+
+// Compact rewrites all current records that have not expired at now into
+// new segments and removes the old ones. A zero now keeps expired records.
+func (s *SegmentStore) Compact(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compact(now)
+}
+
+// compact is safe against crashes: the new segments get higher IDs than
+// the old ones, so if the old segments are not removed, replaying them
+// before the new ones still ends with the same index.
+func (s *SegmentStore) compact(now time.Time) error {
+	old := s.segments
+	oldIndex := s.index
+
+	keys := make([]string, 0, len(oldIndex))
+	for key, loc := range oldIndex {
+		if now.IsZero() || loc.expiresAt.IsZero() || loc.expiresAt.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	// Rewriting in file order keeps reads of the old segments sequential.
+	slices.SortFunc(keys, func(a, b string) int {
+		la, lb := oldIndex[a], oldIndex[b]
+		if la.segment != lb.segment {
+			return la.segment - lb.segment
+		}
+		return int(la.offset - lb.offset)
+	})
+
+	s.segments = nil
+	s.index = make(map[string]location, len(keys))
+	s.live, s.dead = 0, 0
+
+	restore := func(err error) error {
+		for _, seg := range s.segments {
+			seg.file.Close()
+			os.Remove(s.segmentPath(seg.id))
+		}
+		s.segments, s.index = old, oldIndex
+		s.recount()
+
+		return fmt.Errorf("compact: %w", err)
+	}
+
+	if err := s.newSegment(old[len(old)-1].id + 1); err != nil {
+		return restore(err)
+	}
+
+	for _, key := range keys {
+		loc := oldIndex[key]
+
+		buf := make([]byte, loc.size)
+		if _, err := segmentByID(old, loc.segment).file.ReadAt(buf, loc.offset); err != nil {
+			return restore(err)
+		}
+
+		seg := s.active()
+		if seg.size > 0 && seg.size >= s.maxSegmentSize {
+			if err := s.newSegment(seg.id + 1); err != nil {
+				return restore(err)
+			}
+			seg = s.active()
+		}
+
+		if _, err := seg.file.WriteAt(buf, seg.size); err != nil {
+			return restore(err)
+		}
+
+		s.index[key] = location{segment: seg.id, offset: seg.size, size: loc.size, expiresAt: loc.expiresAt}
+		s.live += loc.size
+		seg.size += loc.size
+	}
+
+	for _, seg := range s.segments {
+		if err := seg.file.Sync(); err != nil {
+			return restore(err)
+		}
+	}
+
+	for _, seg := range old {
+		seg.file.Close()
+		if err := os.Remove(s.segmentPath(seg.id)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recount recomputes the live and dead byte counts from the index.
+func (s *SegmentStore) recount() {
+	s.live, s.dead = 0, 0
+	for _, loc := range s.index {
+		s.live += loc.size
+	}
+	for _, seg := range s.segments {
+		s.dead += seg.size
+	}
+	s.dead -= s.live
+}
+
+// Sync flushes the active segment to stable storage.
+func (s *SegmentStore) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.active().file.Sync()
+}
+
+// Close syncs and closes all segments.
+func (s *SegmentStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	if len(s.segments) > 0 {
+		errs = append(errs, s.active().file.Sync())
+	}
+	for _, seg := range s.segments {
+		errs = append(errs, seg.file.Close())
+	}
+	s.segments = nil
+
+	return errors.Join(errs...)
+}
+
+func encodeRecord(kind byte, key, value []byte, expiresAt time.Time) []byte {
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(key)+len(value))
+	record[4] = kind
+	if !expiresAt.IsZero() {
+		binary.BigEndian.PutUint64(record[5:], uint64(expiresAt.UnixNano()))
+	}
+	binary.BigEndian.PutUint32(record[13:], uint32(len(key)))
+	binary.BigEndian.PutUint32(record[17:], uint32(len(value)))
+	record = append(record, key...)
+	record = append(record, value...)
+	binary.BigEndian.PutUint32(record, crc32.Checksum(record[4:], segmentTable))
+
+	return record
+}
+
+// readRecord reads the next record. It returns io.EOF if there is none and
+// ErrCorruptSegment if the record is incomplete or its checksum does not
+// match.
+func readRecord(r io.Reader) (kind byte, expiresAt time.Time, key, value []byte, size int64, err error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return 0, time.Time{}, nil, nil, 0, io.EOF
+		}
+		return 0, time.Time{}, nil, nil, 0, ErrCorruptSegment
+	}
+
+	kind = header[4]
+	if nanos := int64(binary.BigEndian.Uint64(header[5:])); nanos != 0 {
+		expiresAt = time.Unix(0, nanos)
+	}
+	keyLen := binary.BigEndian.Uint32(header[13:])
+	valueLen := binary.BigEndian.Uint32(header[17:])
+
+	if kind != recordPut && kind != recordDelete {
+		return 0, time.Time{}, nil, nil, 0, ErrCorruptSegment
+	}
+
+	// The lengths are not trusted before the checksum has been verified, so
+	// only as much is allocated as can actually be read.
+	dataLen := int64(keyLen) + int64(valueLen)
+	data, err := io.ReadAll(io.LimitReader(r, dataLen))
+	if err != nil || int64(len(data)) != dataLen {
+		return 0, time.Time{}, nil, nil, 0, ErrCorruptSegment
+	}
+
+	crc := crc32.Update(crc32.Checksum(header[4:], segmentTable), segmentTable, data)
+	if crc != binary.BigEndian.Uint32(header) {
+		return 0, time.Time{}, nil, nil, 0, ErrCorruptSegment
+	}
+
+	return kind, expiresAt, data[:keyLen], data[keyLen:], int64(recordHeaderSize) + int64(len(data)), nil
+}
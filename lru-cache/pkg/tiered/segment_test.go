@@ -0,0 +1,189 @@
+package tiered
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T, dir string, maxSegmentSize int64) *SegmentStore {
+	t.Helper()
+
+	s, err := OpenSegmentStore(dir, maxSegmentSize)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func getString(t *testing.T, s *SegmentStore, key string) (string, bool) {
+	t.Helper()
+
+	value, _, found, err := s.Get([]byte(key))
+	require.NoError(t, err)
+
+	return string(value), found
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	require.NoError(t, err)
+
+	return files
+}
+
+func TestSegmentStore(t *testing.T) {
+	t.Run("should store, overwrite and delete values", func(t *testing.T) {
+		s := openStore(t, t.TempDir(), 0)
+
+		require.NoError(t, s.Put([]byte("a"), []byte("1"), time.Time{}))
+		require.NoError(t, s.Put([]byte("a"), []byte("2"), time.Time{}))
+		require.NoError(t, s.Put([]byte("b"), []byte("3"), time.Time{}))
+
+		value, found := getString(t, s, "a")
+		assert.True(t, found)
+		assert.Equal(t, "2", value)
+
+		deleted, err := s.Delete([]byte("a"))
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = s.Delete([]byte("a"))
+		require.NoError(t, err)
+		assert.False(t, deleted)
+
+		_, found = getString(t, s, "a")
+		assert.False(t, found)
+		assert.Equal(t, 1, s.Len())
+	})
+
+	t.Run("should keep expiry times", func(t *testing.T) {
+		s := openStore(t, t.TempDir(), 0)
+		expiresAt := time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC)
+
+		require.NoError(t, s.Put([]byte("a"), []byte("1"), expiresAt))
+
+		_, got, found, err := s.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.True(t, expiresAt.Equal(got))
+	})
+
+	t.Run("should rebuild the index when reopened", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s, err := OpenSegmentStore(dir, 64)
+		require.NoError(t, err)
+		for i := range 10 {
+			require.NoError(t, s.Put(fmt.Appendf(nil, "key-%d", i), fmt.Appendf(nil, "value-%d", i), time.Time{}))
+		}
+		_, err = s.Delete([]byte("key-3"))
+		require.NoError(t, err)
+		require.NoError(t, s.Close())
+		assert.Greater(t, len(segmentFiles(t, dir)), 1)
+
+		s = openStore(t, dir, 64)
+
+		assert.Equal(t, 9, s.Len())
+		value, found := getString(t, s, "key-7")
+		assert.True(t, found)
+		assert.Equal(t, "value-7", value)
+		_, found = getString(t, s, "key-3")
+		assert.False(t, found)
+	})
+
+	t.Run("should truncate a torn record at the end of the newest segment", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s, err := OpenSegmentStore(dir, 0)
+		require.NoError(t, err)
+		require.NoError(t, s.Put([]byte("a"), []byte("1"), time.Time{}))
+		require.NoError(t, s.Put([]byte("b"), []byte("2"), time.Time{}))
+		require.NoError(t, s.Close())
+
+		path := segmentFiles(t, dir)[0]
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(path, info.Size()-1))
+
+		s = openStore(t, dir, 0)
+
+		assert.Equal(t, 1, s.Len())
+		require.NoError(t, s.Put([]byte("c"), []byte("3"), time.Time{}))
+		value, found := getString(t, s, "c")
+		assert.True(t, found)
+		assert.Equal(t, "3", value)
+	})
+
+	t.Run("should reject corrupt records in older segments", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s, err := OpenSegmentStore(dir, 1)
+		require.NoError(t, err)
+		require.NoError(t, s.Put([]byte("a"), []byte("1"), time.Time{}))
+		require.NoError(t, s.Put([]byte("b"), []byte("2"), time.Time{}))
+		require.NoError(t, s.Close())
+
+		path := segmentFiles(t, dir)[0]
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[len(data)-1] ^= 0xff
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		_, err = OpenSegmentStore(dir, 1)
+		assert.ErrorIs(t, err, ErrCorruptSegment)
+	})
+
+	t.Run("should reclaim space and drop expired values when compacting", func(t *testing.T) {
+		dir := t.TempDir()
+		now := time.Date(2025, 4, 7, 9, 0, 0, 0, time.UTC)
+		s := openStore(t, dir, 64)
+
+		for i := range 20 {
+			require.NoError(t, s.Put([]byte("counter"), fmt.Appendf(nil, "%d", i), time.Time{}))
+		}
+		require.NoError(t, s.Put([]byte("expired"), []byte("x"), now.Add(-time.Second)))
+		require.NoError(t, s.Put([]byte("fresh"), []byte("y"), now.Add(time.Second)))
+
+		require.NoError(t, s.Compact(now))
+
+		live, dead := s.Size()
+		assert.Zero(t, dead)
+		assert.Positive(t, live)
+		assert.Equal(t, 2, s.Len())
+
+		value, found := getString(t, s, "counter")
+		assert.True(t, found)
+		assert.Equal(t, "19", value)
+		_, found = getString(t, s, "expired")
+		assert.False(t, found)
+
+		require.NoError(t, s.Close())
+		s = openStore(t, dir, 64)
+		assert.Equal(t, 2, s.Len())
+	})
+
+	t.Run("should compact by itself once most of the log is garbage", func(t *testing.T) {
+		dir := t.TempDir()
+		s := openStore(t, dir, 256)
+
+		for i := range 1000 {
+			require.NoError(t, s.Put([]byte("counter"), fmt.Appendf(nil, "%d", i), time.Time{}))
+		}
+
+		live, dead := s.Size()
+		assert.Less(t, dead, int64(256)+live)
+		assert.LessOrEqual(t, len(segmentFiles(t, dir)), 2)
+
+		value, found := getString(t, s, "counter")
+		assert.True(t, found)
+		assert.Equal(t, "999", value)
+	})
+}
@@ -0,0 +1,264 @@
+// Package tiered provides a two-tier cache: an LRUCache in memory in front
+// of a SegmentStore on disk. Entries evicted from memory for lack of
+// capacity spill to disk, and reading a spilled entry promotes it back into
+// memory. An entry lives in exactly one of the tiers at a time.
+package tiered
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache"
+)
+
+// item is a value in the memory tier together with its expiry time, which
+// is needed when it spills to disk.
+type item[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is a two-tier cache. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	// mu makes moving entries between the tiers atomic. Hits in memory only
+	// need the read lock.
+	mu     sync.RWMutex
+	memory *cache.LRUCache[K, item[V]]
+	disk   *SegmentStore
+	keys   cache.Codec[K]
+	values cache.Codec[V]
+	clock  cache.Clock
+	// spillErr holds the errors of spills since the last Put or Get
+	// returned them, as eviction callbacks cannot return errors themselves.
+	spillErr error
+}
+
+// Option configures a Cache.
+type Option func(*options)
+
+type options struct {
+	maxSegmentSize int64
+	clock          cache.Clock
+}
+
+// WithMaxSegmentSize sets the size at which the disk tier starts a new
+// segment. It defaults to DefaultMaxSegmentSize.
+func WithMaxSegmentSize(size int64) Option {
+	return func(o *options) {
+		o.maxSegmentSize = size
+	}
+}
+
+// WithClock replaces the system clock, e.g. to control expiry in tests.
+func WithClock(clock cache.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// Open creates a cache that keeps up to capacity entries in memory and
+// spills the rest to segments in dir. Entries left on disk by an earlier
+// cache in the same directory are available again. It panics if capacity
+// is not positive.
+func Open[K comparable, V any](capacity int, dir string, keys cache.Codec[K], values cache.Codec[V], opts ...Option) (*Cache[K, V], error) {
+	o := options{clock: cache.SystemClock()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	disk, err := OpenSegmentStore(dir, o.maxSegmentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache[K, V]{disk: disk, keys: keys, values: values, clock: o.clock}
+	c.memory = cache.NewLRUCache(capacity,
+		cache.WithClock[K, item[V]](o.clock),
+		cache.WithOnEvict(c.onEvict),
+	)
+
+	return c, nil
+}
+
+// onEvict spills entries that were evicted for lack of capacity. It is
+// called with c.mu held for writing by the operation that caused the
+// eviction.
+func (c *Cache[K, V]) onEvict(key K, it item[V], reason cache.EvictionReason) {
+	if reason != cache.EvictedCapacity {
+		return
+	}
+
+	if err := c.spill(key, it); err != nil {
+		c.spillErr = errors.Join(c.spillErr, err)
+	}
+}
+
+func (c *Cache[K, V]) spill(key K, it item[V]) error {
+	k, err := c.keys.Encode(key)
+	if err != nil {
+		return err
+	}
+
+	v, err := c.values.Encode(it.value)
+	if err != nil {
+		return err
+	}
+
+	return c.disk.Put(k, v, it.expiresAt)
+}
+
+// takeSpillErr returns and clears the errors of earlier spills.
+func (c *Cache[K, V]) takeSpillErr() error {
+	err := c.spillErr
+	c.spillErr = nil
+
+	return err
+}
+
+// Get returns the value for the key from memory or, promoting it into
+// memory, from disk.
+func (c *Cache[K, V]) Get(key K) (V, bool, error) {
+	c.mu.RLock()
+	it, found := c.memory.Get(key)
+	c.mu.RUnlock()
+	if found {
+		return it.value, true, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another goroutine may have promoted the entry in the meantime.
+	if it, found := c.memory.Get(key); found {
+		return it.value, true, nil
+	}
+
+	it, found, err := c.promote(key)
+	if err != nil || !found {
+		var zero V
+		return zero, false, errors.Join(err, c.takeSpillErr())
+	}
+
+	return it.value, true, c.takeSpillErr()
+}
+
+// promote moves the entry for the key from disk into memory.
+func (c *Cache[K, V]) promote(key K) (item[V], bool, error) {
+	k, err := c.keys.Encode(key)
+	if err != nil {
+		return item[V]{}, false, err
+	}
+
+	data, expiresAt, found, err := c.disk.Get(k)
+	if err != nil || !found {
+		return item[V]{}, false, err
+	}
+
+	if !expiresAt.IsZero() && !expiresAt.After(c.clock.Now()) {
+		_, err := c.disk.Delete(k)
+		return item[V]{}, false, err
+	}
+
+	value, err := c.values.Decode(data)
+	if err != nil {
+		return item[V]{}, false, err
+	}
+
+	// The entry only leaves the disk once it is in memory, so that it is
+	// not lost if it cannot be decoded.
+	it := item[V]{value: value, expiresAt: expiresAt}
+	c.memory.PutWithExpiry(key, it, it.expiresAt)
+
+	if _, err := c.disk.Delete(k); err != nil {
+		return item[V]{}, false, err
+	}
+
+	return it, true, nil
+}
+
+// Put stores the value in memory, spilling the least recently used entry
+// to disk if memory is full.
+func (c *Cache[K, V]) Put(key K, value V) error {
+	return c.put(key, item[V]{value: value})
+}
+
+// PutWithTTL stores the value like Put, expiring it after ttl in either
+// tier. A non-positive ttl means the value never expires.
+func (c *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) error {
+	it := item[V]{value: value}
+	if ttl > 0 {
+		it.expiresAt = c.clock.Now().Add(ttl)
+	}
+
+	return c.put(key, it)
+}
+
+func (c *Cache[K, V]) put(key K, it item[V]) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.deleteDisk(key); err != nil {
+		return err
+	}
+
+	c.memory.PutWithExpiry(key, it, it.expiresAt)
+
+	return c.takeSpillErr()
+}
+
+// Delete removes the key from both tiers and reports whether it was
+// present.
+func (c *Cache[K, V]) Delete(key K) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	inMemory := c.memory.Delete(key)
+	onDisk, err := c.deleteDisk(key)
+
+	return inMemory || onDisk, err
+}
+
+func (c *Cache[K, V]) deleteDisk(key K) (bool, error) {
+	k, err := c.keys.Encode(key)
+	if err != nil {
+		return false, err
+	}
+
+	return c.disk.Delete(k)
+}
+
+// Len returns the number of entries in memory and on disk. Expired entries
+// on disk are counted until they are read or compacted away.
+func (c *Cache[K, V]) Len() (memory, disk int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.memory.Len(), c.disk.Len()
+}
+
+// Compact reclaims the disk space of overwritten, deleted and expired
+// entries.
+func (c *Cache[K, V]) Compact() error {
+	return c.disk.Compact(c.clock.Now())
+}
+
+// Close writes the entries in memory to disk, so that a cache opened on the
+// same directory later finds them, and closes the disk tier.
+func (c *Cache[K, V]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	now := c.clock.Now()
+	for key, it := range c.memory.All() {
+		if it.expiresAt.IsZero() || it.expiresAt.After(now) {
+			errs = append(errs, c.spill(key, it))
+		}
+	}
+	c.memory.Purge()
+
+	errs = append(errs, c.takeSpillErr(), c.disk.Close())
+
+	return errors.Join(errs...)
+}
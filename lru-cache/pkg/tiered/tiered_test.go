@@ -0,0 +1,219 @@
+package tiered
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baschtl/lru-cache/pkg/cache"
+	"github.com/baschtl/lru-cache/pkg/cache/cachetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingCodec fails to decode values while fail is set.
+type failingCodec struct {
+	cache.GobCodec[int]
+	fail bool
+}
+
+func (c *failingCodec) Decode(data []byte) (int, error) {
+	if c.fail {
+		return 0, errors.New("decode failed")
+	}
+
+	return c.GobCodec.Decode(data)
+}
+
+func openCache(t *testing.T, capacity int, dir string, opts ...Option) *Cache[string, int] {
+	t.Helper()
+
+	c, err := Open(capacity, dir, cache.GobCodec[string]{}, cache.GobCodec[int]{}, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func requireValue(t *testing.T, c *Cache[string, int], key string, want int) {
+	t.Helper()
+
+	value, found, err := c.Get(key)
+	require.NoError(t, err)
+	require.True(t, found, key)
+	assert.Equal(t, want, value, key)
+}
+
+func TestCache(t *testing.T) {
+	t.Run("should spill evicted entries to disk and promote them on Get", func(t *testing.T) {
+		c := openCache(t, 2, t.TempDir())
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Put("b", 2))
+		require.NoError(t, c.Put("c", 3))
+
+		memory, disk := c.Len()
+		assert.Equal(t, 2, memory)
+		assert.Equal(t, 1, disk)
+
+		requireValue(t, c, "a", 1)
+
+		// Promoting a spills the least recently used b to make room.
+		memory, disk = c.Len()
+		assert.Equal(t, 2, memory)
+		assert.Equal(t, 1, disk)
+		requireValue(t, c, "b", 2)
+		requireValue(t, c, "c", 3)
+	})
+
+	t.Run("should prefer new values over spilled ones", func(t *testing.T) {
+		c := openCache(t, 1, t.TempDir())
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Put("b", 2))
+		require.NoError(t, c.Put("a", 10))
+
+		requireValue(t, c, "a", 10)
+		requireValue(t, c, "b", 2)
+		requireValue(t, c, "a", 10)
+	})
+
+	t.Run("should delete entries from both tiers", func(t *testing.T) {
+		c := openCache(t, 1, t.TempDir())
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Put("b", 2))
+
+		for _, key := range []string{"a", "b"} {
+			deleted, err := c.Delete(key)
+			require.NoError(t, err)
+			assert.True(t, deleted, key)
+
+			_, found, err := c.Get(key)
+			require.NoError(t, err)
+			assert.False(t, found, key)
+		}
+
+		deleted, err := c.Delete("a")
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+
+	t.Run("should expire entries in either tier", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		c := openCache(t, 1, t.TempDir(), WithClock(clock))
+
+		require.NoError(t, c.PutWithTTL("a", 1, time.Minute))
+		require.NoError(t, c.PutWithTTL("b", 2, time.Minute))
+
+		clock.Advance(time.Minute)
+
+		for _, key := range []string{"a", "b"} {
+			_, found, err := c.Get(key)
+			require.NoError(t, err)
+			assert.False(t, found, key)
+		}
+	})
+
+	t.Run("should never expire entries with a non-positive ttl in either tier", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		c := openCache(t, 1, t.TempDir(), WithClock(clock))
+
+		require.NoError(t, c.PutWithTTL("a", 1, 0))
+		require.NoError(t, c.PutWithTTL("b", 2, -time.Minute))
+		require.NoError(t, c.Put("c", 3))
+
+		clock.Advance(time.Hour)
+
+		requireValue(t, c, "a", 1)
+		requireValue(t, c, "b", 2)
+	})
+
+	t.Run("should keep the expiry time when promoting", func(t *testing.T) {
+		clock := cachetest.NewClock()
+		c := openCache(t, 1, t.TempDir(), WithClock(clock))
+
+		require.NoError(t, c.PutWithTTL("a", 1, time.Minute))
+		require.NoError(t, c.Put("b", 2))
+		requireValue(t, c, "a", 1)
+
+		clock.Advance(time.Minute)
+
+		_, found, err := c.Get("a")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("should keep entries on disk that cannot be decoded", func(t *testing.T) {
+		values := &failingCodec{}
+		c, err := Open(1, t.TempDir(), cache.GobCodec[string]{}, values)
+		require.NoError(t, err)
+		t.Cleanup(func() { c.Close() })
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Put("b", 2))
+
+		values.fail = true
+		_, _, err = c.Get("a")
+		assert.Error(t, err)
+
+		values.fail = false
+		value, found, err := c.Get("a")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("should find all entries again after a restart", func(t *testing.T) {
+		dir := t.TempDir()
+
+		c, err := Open(2, dir, cache.GobCodec[string]{}, cache.GobCodec[int]{})
+		require.NoError(t, err)
+		for i := range 5 {
+			require.NoError(t, c.Put(fmt.Sprint(i), i))
+		}
+		require.NoError(t, c.Close())
+
+		c = openCache(t, 2, dir)
+
+		memory, disk := c.Len()
+		assert.Zero(t, memory)
+		assert.Equal(t, 5, disk)
+		for i := range 5 {
+			requireValue(t, c, fmt.Sprint(i), i)
+		}
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		c := openCache(t, 8, t.TempDir(), WithMaxSegmentSize(1024))
+
+		var wg sync.WaitGroup
+		for g := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for i := range 200 {
+					key := fmt.Sprint((g*7 + i) % 32)
+					switch i % 3 {
+					case 0:
+						assert.NoError(t, c.Put(key, i))
+					case 1:
+						_, _, err := c.Get(key)
+						assert.NoError(t, err)
+					case 2:
+						_, err := c.Delete(key)
+						assert.NoError(t, err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		memory, disk := c.Len()
+		assert.LessOrEqual(t, memory, 8)
+		assert.LessOrEqual(t, memory+disk, 32)
+	})
+}
@@ -0,0 +1,7 @@
+module github.com/baschtl/web-server
+
+go 1.24.1
+
+replace github.com/baschtl/lru-cache => ../lru-cache
+
+require github.com/baschtl/lru-cache v0.0.0-00010101000000-000000000000
@@ -9,6 +9,8 @@ import (
 	"os"
 	"os/signal"
 	"time"
+
+	"github.com/baschtl/lru-cache/pkg/httpcache"
 )
 
 type Response struct {
@@ -28,6 +30,7 @@ func GetHello(w http.ResponseWriter, req *http.Request) {
 	msg := Response{Message: "hello"}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "max-age=60")
 	json.NewEncoder(w).Encode(msg)
 }
 
@@ -45,14 +48,18 @@ var startTime time.Time
 
 const port = 8090
 
+// cacheBytes bounds the memory used for cached responses.
+const cacheBytes = 16 << 20
+
 func main() {
 	startTime = time.Now()
 
 	mux := http.NewServeMux()
 
 	logRouter := loggingMiddleware(mux)
+	responseCache := httpcache.New(cacheBytes)
 
-	mux.HandleFunc("/hello", GetHello)
+	mux.Handle("/hello", responseCache.Middleware(http.HandlerFunc(GetHello)))
 	mux.HandleFunc("/status", GetStatus)
 
 	server := &http.Server{